@@ -0,0 +1,311 @@
+package selenium
+
+import (
+	"errors"
+	"time"
+
+	"./keys"
+)
+
+//InputSourceType identifies the kind of input device an action sequence belongs to
+type InputSourceType string
+
+//Constants for InputSourceType
+const (
+	NoneInput    InputSourceType = "none"
+	KeyInput     InputSourceType = "key"
+	PointerInput InputSourceType = "pointer"
+	WheelInput   InputSourceType = "wheel"
+)
+
+//PointerType indicates the kind of pointing device driven by a pointer input source
+type PointerType string
+
+//Constants for PointerType
+const (
+	Mouse PointerType = "mouse"
+	Pen   PointerType = "pen"
+	Touch PointerType = "touch"
+)
+
+//MouseButton is the W3C button number used by pointerDown and pointerUp actions
+type MouseButton int
+
+//Constants for MouseButton
+const (
+	LeftButton   MouseButton = 0
+	MiddleButton MouseButton = 1
+	RightButton  MouseButton = 2
+)
+
+//Origins accepted by pointerMove and scroll actions besides a WebElement
+const (
+	ViewportOrigin = "viewport"
+	PointerOrigin  = "pointer"
+)
+
+//InputSource is a single W3C action sequence: one input device and the action performed by it on each tick
+type InputSource struct {
+	Type       InputSourceType          `json:"type"`
+	ID         string                   `json:"id"`
+	Parameters map[string]interface{}   `json:"parameters,omitempty"`
+	Actions    []map[string]interface{} `json:"actions"`
+}
+
+//Actions builds the list of action sequences sent with the "Perform Actions" command.
+//Every call adds one tick: the target input source performs the action while every other source pauses,
+//so actions are dispatched in the order they were added.
+type Actions struct {
+	none    *InputSource
+	key     *InputSource
+	pointer *InputSource
+	wheel   *InputSource
+	sources []*InputSource
+	ticks   int
+	err     error
+}
+
+//NewActions returns an empty action builder using a mouse pointer
+func NewActions() *Actions {
+	return &Actions{}
+}
+
+//WithPointerType sets the type of pointing device used by pointer actions, which also names the pointer input source
+func (a *Actions) WithPointerType(pointerType PointerType) *Actions {
+	source := a.pointerSource()
+	source.ID = string(pointerType)
+	source.Parameters["pointerType"] = pointerType
+	return a
+}
+
+//Sources returns the action sequences built so far
+func (a *Actions) Sources() []*InputSource {
+
+	//an empty list, not null, is sent when no action was added
+	if a.sources == nil {
+		return make([]*InputSource, 0)
+	}
+
+	return a.sources
+
+}
+
+//Err returns the first error encountered while building the actions
+func (a *Actions) Err() error {
+	return a.err
+}
+
+//Pause adds a tick during which every input source idles for the given duration
+func (a *Actions) Pause(duration time.Duration) *Actions {
+
+	a.noneSource()
+
+	for _, source := range a.sources {
+		source.Actions = append(source.Actions, map[string]interface{}{
+			"type":     "pause",
+			"duration": int(duration / time.Millisecond),
+		})
+	}
+
+	a.ticks++
+	return a
+
+}
+
+//KeyDown presses the given key without releasing it
+func (a *Actions) KeyDown(key keys.Key) *Actions {
+	return a.tick(a.keySource(), map[string]interface{}{"type": "keyDown", "value": string(key)})
+}
+
+//KeyUp releases the given key
+func (a *Actions) KeyUp(key keys.Key) *Actions {
+	return a.tick(a.keySource(), map[string]interface{}{"type": "keyUp", "value": string(key)})
+}
+
+//SendKeys presses and releases every character of text in turn
+func (a *Actions) SendKeys(text string) *Actions {
+
+	for _, c := range text {
+		a.KeyDown(keys.Key(c)).KeyUp(keys.Key(c))
+	}
+
+	return a
+
+}
+
+//PointerDown presses the given button of the pointer
+func (a *Actions) PointerDown(button MouseButton) *Actions {
+	return a.tick(a.pointerSource(), map[string]interface{}{"type": "pointerDown", "button": button})
+}
+
+//PointerUp releases the given button of the pointer
+func (a *Actions) PointerUp(button MouseButton) *Actions {
+	return a.tick(a.pointerSource(), map[string]interface{}{"type": "pointerUp", "button": button})
+}
+
+//MoveTo moves the pointer to the given viewport coordinates
+func (a *Actions) MoveTo(x int, y int) *Actions {
+	return a.move(x, y, ViewportOrigin)
+}
+
+//MoveBy moves the pointer by the given offset from its current position
+func (a *Actions) MoveBy(x int, y int) *Actions {
+	return a.move(x, y, PointerOrigin)
+}
+
+//MoveToElement moves the pointer to the given offset from the in-view center point of element
+func (a *Actions) MoveToElement(element WebElement, x int, y int) *Actions {
+
+	origin, err := elementReference(element)
+	if err != nil {
+		a.setErr(err)
+		return a
+	}
+
+	return a.move(x, y, origin)
+
+}
+
+//Click presses and releases the left button at the current pointer position
+func (a *Actions) Click() *Actions {
+	return a.PointerDown(LeftButton).PointerUp(LeftButton)
+}
+
+//ClickElement moves the pointer to the center of element and clicks it
+func (a *Actions) ClickElement(element WebElement) *Actions {
+	return a.MoveToElement(element, 0, 0).Click()
+}
+
+//DoubleClick clicks the left button twice at the current pointer position
+func (a *Actions) DoubleClick() *Actions {
+	return a.Click().Click()
+}
+
+//ContextClick presses and releases the right button at the current pointer position
+func (a *Actions) ContextClick() *Actions {
+	return a.PointerDown(RightButton).PointerUp(RightButton)
+}
+
+//DragAndDrop holds the left button on source, moves to target and releases it
+func (a *Actions) DragAndDrop(source WebElement, target WebElement) *Actions {
+	return a.MoveToElement(source, 0, 0).
+		PointerDown(LeftButton).
+		MoveToElement(target, 0, 0).
+		PointerUp(LeftButton)
+}
+
+//Scroll scrolls by deltaX and deltaY with the wheel positioned at the given viewport coordinates
+func (a *Actions) Scroll(x int, y int, deltaX int, deltaY int) *Actions {
+	return a.scroll(x, y, deltaX, deltaY, ViewportOrigin)
+}
+
+//ScrollFromElement scrolls by deltaX and deltaY with the wheel positioned at the given offset from the center of element
+func (a *Actions) ScrollFromElement(element WebElement, x int, y int, deltaX int, deltaY int) *Actions {
+
+	origin, err := elementReference(element)
+	if err != nil {
+		a.setErr(err)
+		return a
+	}
+
+	return a.scroll(x, y, deltaX, deltaY, origin)
+
+}
+
+func (a *Actions) move(x int, y int, origin interface{}) *Actions {
+	return a.tick(a.pointerSource(), map[string]interface{}{
+		"type":     "pointerMove",
+		"duration": 0,
+		"origin":   origin,
+		"x":        x,
+		"y":        y,
+	})
+}
+
+func (a *Actions) scroll(x int, y int, deltaX int, deltaY int, origin interface{}) *Actions {
+	return a.tick(a.wheelSource(), map[string]interface{}{
+		"type":     "scroll",
+		"duration": 0,
+		"origin":   origin,
+		"x":        x,
+		"y":        y,
+		"deltaX":   deltaX,
+		"deltaY":   deltaY,
+	})
+}
+
+func (a *Actions) tick(target *InputSource, action map[string]interface{}) *Actions {
+
+	for _, source := range a.sources {
+		if source == target {
+			source.Actions = append(source.Actions, action)
+		} else {
+			source.Actions = append(source.Actions, map[string]interface{}{"type": "pause", "duration": 0})
+		}
+	}
+
+	a.ticks++
+	return a
+
+}
+
+func (a *Actions) setErr(err error) {
+	if a.err == nil {
+		a.err = err
+	}
+}
+
+func (a *Actions) addSource(sourceType InputSourceType, id string) *InputSource {
+
+	source := &InputSource{Type: sourceType, ID: id, Actions: make([]map[string]interface{}, 0)}
+
+	//pad the new source so that its actions stay aligned with the ticks already built
+	for i := 0; i < a.ticks; i++ {
+		source.Actions = append(source.Actions, map[string]interface{}{"type": "pause", "duration": 0})
+	}
+
+	a.sources = append(a.sources, source)
+	return source
+
+}
+
+func (a *Actions) noneSource() *InputSource {
+	if a.none == nil {
+		a.none = a.addSource(NoneInput, "none")
+	}
+	return a.none
+}
+
+func (a *Actions) keySource() *InputSource {
+	if a.key == nil {
+		a.key = a.addSource(KeyInput, "keyboard")
+	}
+	return a.key
+}
+
+func (a *Actions) pointerSource() *InputSource {
+	if a.pointer == nil {
+		a.pointer = a.addSource(PointerInput, string(Mouse))
+		a.pointer.Parameters = map[string]interface{}{"pointerType": Mouse}
+	}
+	return a.pointer
+}
+
+func (a *Actions) wheelSource() *InputSource {
+	if a.wheel == nil {
+		a.wheel = a.addSource(WheelInput, "wheel")
+	}
+	return a.wheel
+}
+
+//elementReference returns the web element reference object identifying element on the wire
+func elementReference(element WebElement) (map[string]interface{}, error) {
+
+	info, ok := element.(WebElementInfo)
+	if !ok {
+		return nil, errors.New("could not get web element info")
+	}
+
+	return map[string]interface{}{info.GetID(): info.GetValue()}, nil
+
+}
@@ -0,0 +1,74 @@
+package selenium
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"./keys"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActions(t *testing.T) {
+
+	element := &webElement{id: "element-6066-11e4-a52e-4f735466cecf", value: "abc"}
+
+	actions := NewActions().
+		KeyDown(keys.Shift).
+		ClickElement(element).
+		KeyUp(keys.Shift).
+		Pause(500 * time.Millisecond)
+
+	require.NoErrorf(t, actions.Err(), "Building actions should not raise any errors.")
+
+	data, err := json.Marshal(actions.Sources())
+	require.NoErrorf(t, err, "Serializing actions should not raise any errors.")
+
+	require.JSONEqf(t, `[
+		{"type": "key", "id": "keyboard", "actions": [
+			{"type": "keyDown", "value": "\ue008"},
+			{"type": "pause", "duration": 0},
+			{"type": "pause", "duration": 0},
+			{"type": "pause", "duration": 0},
+			{"type": "keyUp", "value": "\ue008"},
+			{"type": "pause", "duration": 500}
+		]},
+		{"type": "pointer", "id": "mouse", "parameters": {"pointerType": "mouse"}, "actions": [
+			{"type": "pause", "duration": 0},
+			{"type": "pointerMove", "duration": 0, "origin": {"element-6066-11e4-a52e-4f735466cecf": "abc"}, "x": 0, "y": 0},
+			{"type": "pointerDown", "button": 0},
+			{"type": "pointerUp", "button": 0},
+			{"type": "pause", "duration": 0},
+			{"type": "pause", "duration": 500}
+		]},
+		{"type": "none", "id": "none", "actions": [
+			{"type": "pause", "duration": 0},
+			{"type": "pause", "duration": 0},
+			{"type": "pause", "duration": 0},
+			{"type": "pause", "duration": 0},
+			{"type": "pause", "duration": 0},
+			{"type": "pause", "duration": 500}
+		]}
+	]`, string(data), "Serialized actions should match the W3C action sequence format.")
+
+}
+
+func TestActionsPointerType(t *testing.T) {
+
+	actions := NewActions().WithPointerType(Pen).MoveTo(10, 20)
+
+	require.Equalf(t, "pen", actions.Sources()[0].ID, "Pointer source should be named after its pointer type.")
+	require.Equalf(t, Pen, actions.Sources()[0].Parameters["pointerType"], "Pointer type should be sent as a parameter.")
+
+}
+
+func TestPerformActions(t *testing.T) {
+
+	server, wd := newCommandServer(t)
+	defer server.Close()
+
+	require.NoErrorf(t, wd.PerformActions(NewActions()), "Performing no actions should succeed.")
+	require.Equalf(t, "POST /session/1/actions", server.command, "Actions should be sent to the session.")
+	require.Equalf(t, map[string]interface{}{"actions": []interface{}{}}, server.payload, "An empty list of actions should be sent.")
+
+}
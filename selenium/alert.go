@@ -0,0 +1,34 @@
+package selenium
+
+type alert struct {
+	driver WebDriver
+}
+
+//Accept accepts the user prompt
+func (a *alert) Accept() error { return a.driver.AcceptAlert() }
+
+//Dismiss dismisses the user prompt
+func (a *alert) Dismiss() error { return a.driver.DismissAlert() }
+
+//GetText returns the message of the user prompt
+func (a *alert) GetText() (string, error) { return a.driver.GetAlertText() }
+
+//SendKeys sets the text field of a window.prompt() user prompt
+func (a *alert) SendKeys(text string) error { return a.driver.SendAlertText(text) }
+
+//Alert provides an interface to the currently displayed alert, confirm or prompt user prompt
+type Alert interface {
+	Accept() error
+	Dismiss() error
+	GetText() (string, error)
+	SendKeys(text string) error
+}
+
+//Values accepted by Capabilities.SetUnhandledPromptBehavior
+const (
+	DismissPrompt          = "dismiss"
+	AcceptPrompt           = "accept"
+	DismissAndNotifyPrompt = "dismiss and notify"
+	AcceptAndNotifyPrompt  = "accept and notify"
+	IgnorePrompt           = "ignore"
+)
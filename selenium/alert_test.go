@@ -0,0 +1,46 @@
+package selenium
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlert(t *testing.T) {
+
+	server, wd := newCommandServer(t)
+	defer server.Close()
+
+	server.value = "Are you sure?"
+
+	alert, err := wd.SwitchToAlert()
+	require.NoErrorf(t, err, "Switching to the alert should succeed.")
+
+	text, err := alert.GetText()
+	require.NoErrorf(t, err, "Alert text should be read.")
+	require.Equalf(t, "Are you sure?", text, "Alert text should be read from the reply value.")
+	require.Equalf(t, "GET /session/1/alert/text", server.command, "Alert text should be requested.")
+
+	server.value = nil
+
+	require.NoErrorf(t, alert.SendKeys("yes"), "Prompt text should be sent.")
+	require.Equalf(t, "POST /session/1/alert/text", server.command, "Prompt text should be posted.")
+	require.Equalf(t, map[string]interface{}{"text": "yes"}, server.payload, "Prompt text should be sent as text.")
+
+	require.NoErrorf(t, alert.Accept(), "Alert should be accepted.")
+	require.Equalf(t, "POST /session/1/alert/accept", server.command, "Accept should be posted.")
+
+	require.NoErrorf(t, alert.Dismiss(), "Alert should be dismissed.")
+	require.Equalf(t, "POST /session/1/alert/dismiss", server.command, "Dismiss should be posted.")
+
+	server.status = http.StatusInternalServerError
+	server.value = map[string]interface{}{"error": "unexpected alert open", "message": "", "data": map[string]interface{}{"text": "Leave page?"}}
+
+	_, err = wd.GetTitle()
+	var alertError *UnexpectedAlertOpenError
+	require.Truef(t, errors.As(err, &alertError), "Command blocked by a prompt should return an unexpected alert open error.")
+	require.Equalf(t, "Leave page?", alertError.Text, "Error should carry the alert text.")
+
+}
@@ -0,0 +1,212 @@
+package bidi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"../../selenium"
+	"../websocket"
+	"github.com/stretchr/testify/require"
+)
+
+//newServer returns a fake BiDi remote end with a single browsing context "top"
+func newServer(t *testing.T) *httptest.Server {
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		ws, err := websocket.Upgrade(w, r)
+		if err != nil {
+			return
+		}
+		defer ws.Close()
+
+		for {
+
+			data, err := ws.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			received := struct {
+				ID     int64                  `json:"id"`
+				Method string                 `json:"method"`
+				Params map[string]interface{} `json:"params"`
+			}{}
+			require.NoErrorf(t, json.Unmarshal(data, &received), "Command should be JSON.")
+
+			var result interface{} = map[string]interface{}{}
+
+			switch received.Method {
+			case "browsingContext.getTree":
+				result = map[string]interface{}{"contexts": []interface{}{
+					map[string]interface{}{"context": "top", "url": "about:blank", "children": []interface{}{}},
+				}}
+			case "browsingContext.navigate":
+				ws.WriteMessage([]byte(`{"type": "event", "method": "log.entryAdded", "params": {
+					"type": "javascript", "level": "error", "text": "ReferenceError: foo is not defined",
+					"source": {"realm": "1", "context": "top"}, "timestamp": 1700000000000}}`))
+				result = map[string]interface{}{"navigation": "n1", "url": received.Params["url"]}
+			case "script.evaluate":
+				if received.Params["expression"] == "undefinedFunction()" {
+					result = map[string]interface{}{"type": "exception", "realm": "1", "exceptionDetails": map[string]interface{}{
+						"text": "ReferenceError: undefinedFunction is not defined", "lineNumber": 0, "columnNumber": 0,
+					}}
+				} else {
+					result = map[string]interface{}{"type": "success", "realm": "1", "result": map[string]interface{}{"type": "number", "value": 2}}
+				}
+			case "script.callFunction":
+				//returns its first argument, which local and remote values serialize alike
+				result = map[string]interface{}{"type": "success", "realm": "1", "result": received.Params["arguments"].([]interface{})[0]}
+			case "session.subscribe":
+			default:
+				reply, _ := json.Marshal(map[string]interface{}{"type": "error", "id": received.ID, "error": "unknown command", "message": received.Method})
+				ws.WriteMessage(reply)
+				continue
+			}
+
+			reply, _ := json.Marshal(map[string]interface{}{"type": "success", "id": received.ID, "result": result})
+			ws.WriteMessage(reply)
+
+		}
+
+	}))
+
+}
+
+func TestClient(t *testing.T) {
+
+	server := newServer(t)
+	defer server.Close()
+
+	remote := selenium.NewRemote(server.URL, selenium.NewCapabilities())
+	remote.SetSession("session-id", map[string]interface{}{"webSocketUrl": "ws" + strings.TrimPrefix(server.URL, "http") + "/session/session-id"})
+
+	client, err := Connect(remote)
+	require.NoErrorf(t, err, "Client should connect to the webSocketUrl of the session.")
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	events, unsubscribe := client.Events(LogEntryAdded)
+	defer unsubscribe()
+	require.NoErrorf(t, client.Subscribe(ctx, []string{LogEntryAdded}), "Events should be subscribed to.")
+
+	tree, err := client.GetTree(ctx, "", 0)
+	require.NoErrorf(t, err, "Tree should be returned.")
+	require.Equalf(t, "top", tree[0].Context, "Top-level context should be listed.")
+
+	navigation, err := client.Navigate(ctx, "top", "http://localhost/", ReadinessComplete)
+	require.NoErrorf(t, err, "Navigation should succeed.")
+	require.Equalf(t, "http://localhost/", navigation.URL, "Navigated URL should be returned.")
+
+	select {
+	case event := <-events:
+		entry := new(LogEntry)
+		require.NoErrorf(t, event.Decode(entry), "Log entry should be decoded.")
+		require.Equalf(t, LevelError, entry.Level, "Level should be decoded.")
+		require.Equalf(t, "top", entry.Source.Context, "Source should be decoded.")
+	case <-ctx.Done():
+		t.Fatal("Log entry should be received.")
+	}
+
+	value, err := client.Evaluate(ctx, "top", "1 + 1", false)
+	require.NoErrorf(t, err, "Expression should be evaluated.")
+	number, err := value.Interface()
+	require.NoErrorf(t, err, "Result should be converted.")
+	require.Equalf(t, 2.0, number, "Result should be a number.")
+
+	_, err = client.Evaluate(ctx, "top", "undefinedFunction()", false)
+	exception := new(ScriptException)
+	require.ErrorAsf(t, err, &exception, "Thrown exception should be returned.")
+
+	value, err = client.CallFunction(ctx, "top", "(value) => value", false, map[string]interface{}{"items": []interface{}{"a", true, nil}})
+	require.NoErrorf(t, err, "Function should be called.")
+	converted, err := value.Interface()
+	require.NoErrorf(t, err, "Result should be converted.")
+	require.Equalf(t, map[string]interface{}{"items": []interface{}{"a", true, nil}}, converted, "Arguments should round trip.")
+
+	_, err = client.Execute(ctx, "browser.close", nil)
+	bidiErr := new(Error)
+	require.ErrorAsf(t, err, &bidiErr, "Command error should be returned.")
+	require.Equalf(t, "unknown command", bidiErr.Code, "Error code should be decoded.")
+
+}
+
+func TestConnectWithoutWebSocketURL(t *testing.T) {
+
+	remote := selenium.NewRemote("http://127.0.0.1:4444", selenium.NewCapabilities())
+	remote.SetSession("session-id", map[string]interface{}{})
+
+	_, err := Connect(remote)
+	require.Errorf(t, err, "Session without webSocketUrl should not connect.")
+
+}
+
+func TestConnectLocalDriver(t *testing.T) {
+
+	server := newServer(t)
+	defer server.Close()
+
+	remote := selenium.NewRemote(server.URL, selenium.NewCapabilities())
+	remote.SetSession("session-id", map[string]interface{}{"webSocketUrl": "ws" + strings.TrimPrefix(server.URL, "http") + "/session/session-id"})
+
+	//the drivers of the browser packages, e.g. firefox.Driver, wrap a LocalDriver
+	driver := &selenium.LocalDriver{WebDriver: remote}
+
+	client, err := Connect(driver)
+	require.NoErrorf(t, err, "Client should connect to the webSocketUrl of a local driver session.")
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tree, err := client.GetTree(ctx, "", 0)
+	require.NoErrorf(t, err, "Tree should be returned.")
+	require.Equalf(t, "top", tree[0].Context, "Top-level context should be listed.")
+
+}
+
+func TestLocalValueNumbers(t *testing.T) {
+
+	numbers := []struct {
+		value    interface{}
+		expected interface{}
+	}{
+		{math.NaN(), "NaN"},
+		{math.Inf(1), "Infinity"},
+		{math.Inf(-1), "-Infinity"},
+		{math.Copysign(0, -1), "-0"},
+		{float32(math.Inf(-1)), "-Infinity"},
+		{0.0, 0.0},
+		{1.5, 1.5},
+		{42, 42.0},
+	}
+
+	for _, number := range numbers {
+
+		data, err := json.Marshal(LocalValue(number.value))
+		require.NoErrorf(t, err, "Number %v should be serialized.", number.value)
+
+		serialized := make(map[string]interface{})
+		require.NoErrorf(t, json.Unmarshal(data, &serialized), "Serialized number should be JSON.")
+		require.Equalf(t, "number", serialized["type"], "Number %v should keep the number type.", number.value)
+		require.Equalf(t, number.expected, serialized["value"], "Number %v should be serialized as BiDi expects.", number.value)
+
+		//the remote end serializes numbers alike, so the value round trips
+		remote := new(RemoteValue)
+		require.NoErrorf(t, json.Unmarshal(data, remote), "Serialized number should decode as a remote value.")
+		converted, err := remote.Interface()
+		require.NoErrorf(t, err, "Remote value should be converted.")
+		require.Equalf(t, fmt.Sprint(number.value), fmt.Sprint(converted), "Number %v should round trip.", number.value)
+
+	}
+
+}
@@ -0,0 +1,121 @@
+package bidi
+
+import (
+	"context"
+	"encoding/base64"
+
+	"../../selenium"
+)
+
+//Browsing context types of CreateBrowsingContext
+const (
+	Tab    = "tab"
+	Window = "window"
+)
+
+//Readiness states waited for by Navigate
+const (
+	ReadinessNone        = "none"
+	ReadinessInteractive = "interactive"
+	ReadinessComplete    = "complete"
+)
+
+//BrowsingContextInfo describes a browsing context, i.e. a window, tab or frame, and its children
+type BrowsingContextInfo struct {
+	Context  string                 `json:"context"`
+	URL      string                 `json:"url"`
+	Parent   string                 `json:"parent,omitempty"`
+	Children []*BrowsingContextInfo `json:"children"`
+}
+
+//NavigateResult is the result of Navigate
+type NavigateResult struct {
+	Navigation string `json:"navigation"`
+	URL        string `json:"url"`
+}
+
+//CreateBrowsingContext opens a Tab or a Window and returns its browsing context
+func (client *Client) CreateBrowsingContext(ctx context.Context, contextType string) (string, error) {
+
+	result := struct {
+		Context string `json:"context"`
+	}{}
+
+	err := client.execute(ctx, "browsingContext.create", map[string]interface{}{"type": contextType}, &result)
+	if err != nil {
+		return "", err
+	}
+
+	return result.Context, nil
+
+}
+
+//CloseBrowsingContext closes a top-level browsing context
+func (client *Client) CloseBrowsingContext(ctx context.Context, browsingContext string) error {
+	return client.execute(ctx, "browsingContext.close", map[string]interface{}{"context": browsingContext}, nil)
+}
+
+//Navigate navigates the browsing context to url and waits for the readiness state, e.g. ReadinessComplete
+func (client *Client) Navigate(ctx context.Context, browsingContext string, url string, wait string) (*NavigateResult, error) {
+
+	params := map[string]interface{}{"context": browsingContext, "url": url}
+	if wait != "" {
+		params["wait"] = wait
+	}
+
+	result := new(NavigateResult)
+
+	err := client.execute(ctx, "browsingContext.navigate", params, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+
+}
+
+//GetTree returns the tree of browsing contexts below root, or of every top-level context if root is empty.
+//A maxDepth of 0 returns the whole tree.
+func (client *Client) GetTree(ctx context.Context, root string, maxDepth int) ([]*BrowsingContextInfo, error) {
+
+	params := make(map[string]interface{})
+	if root != "" {
+		params["root"] = root
+	}
+	if maxDepth > 0 {
+		params["maxDepth"] = maxDepth
+	}
+
+	result := struct {
+		Contexts []*BrowsingContextInfo `json:"contexts"`
+	}{}
+
+	err := client.execute(ctx, "browsingContext.getTree", params, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.Contexts, nil
+
+}
+
+//CaptureScreenshot takes a screenshot of the viewport of the browsing context
+func (client *Client) CaptureScreenshot(ctx context.Context, browsingContext string) (selenium.Screenshot, error) {
+
+	result := struct {
+		Data string `json:"data"`
+	}{}
+
+	err := client.execute(ctx, "browsingContext.captureScreenshot", map[string]interface{}{"context": browsingContext}, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := base64.StdEncoding.DecodeString(result.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	return selenium.Screenshot(data), nil
+
+}
@@ -0,0 +1,181 @@
+//Package bidi implements a WebDriver BiDi client, connected to the browser of a session started with the
+//webSocketUrl capability, e.g. with options.SetWebSocketURL(true)
+package bidi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"../../selenium"
+	"../errors"
+	"../websocket"
+)
+
+//Error is the error of a BiDi command
+type Error struct {
+	Code       string `json:"error"`
+	Message    string `json:"message"`
+	Stacktrace string `json:"stacktrace,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+//Event is an event the client is subscribed to, e.g. "log.entryAdded"
+type Event struct {
+	Method string
+	Params json.RawMessage
+}
+
+//Decode decodes the parameters of the event into v, e.g. a *LogEntry or a *NetworkEvent
+func (event *Event) Decode(v interface{}) error {
+	return json.Unmarshal(event.Params, v)
+}
+
+type message struct {
+	Type   string          `json:"type,omitempty"`
+	ID     int64           `json:"id,omitempty"`
+	Method string          `json:"method,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+	Result json.RawMessage `json:"result,omitempty"`
+	*Error
+}
+
+type command struct {
+	ID     int64       `json:"id"`
+	Method string      `json:"method"`
+	Params interface{} `json:"params"`
+}
+
+//Client is a WebDriver BiDi client. Commands are multiplexed by id and events are delivered on channels,
+//so the client is safe for concurrent use.
+type Client struct {
+	rpc *websocket.RPC
+}
+
+//Dial connects to the BiDi endpoint webSocketURL
+func Dial(ctx context.Context, webSocketURL string) (*Client, error) {
+
+	conn, err := websocket.Dial(ctx, webSocketURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{websocket.NewRPC(conn, route)}, nil
+
+}
+
+//Connect connects to the BiDi endpoint returned in the webSocketUrl capability of the driver session
+func Connect(driver selenium.WebDriver) (*Client, error) {
+
+	info, ok := driver.(selenium.WebDriverInfo)
+	if !ok {
+		return nil, errors.WebDriverInfoNotImplemented()
+	}
+
+	webSocketURL, _ := info.GetSession().GetCapabilities()["webSocketUrl"].(string)
+	if webSocketURL == "" {
+		return nil, fmt.Errorf("session has no webSocketUrl capability, request it with SetWebSocketURL(true)")
+	}
+
+	return Dial(info.GetContext(), webSocketURL)
+
+}
+
+//Execute sends the command method with params and waits for its result, or until ctx is done
+func (client *Client) Execute(ctx context.Context, method string, params interface{}) (json.RawMessage, error) {
+
+	if params == nil {
+		params = struct{}{}
+	}
+
+	id := client.rpc.NextID()
+
+	data, err := json.Marshal(&command{ID: id, Method: method, Params: params})
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = client.rpc.Call(ctx, id, data)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := new(message)
+	err = json.Unmarshal(data, reply)
+	if err != nil {
+		return nil, err
+	}
+
+	if reply.Error != nil && reply.Error.Code != "" {
+		return nil, reply.Error
+	}
+
+	return reply.Result, nil
+
+}
+
+//route tells the events from the replies, which carry the id of their command
+func route(data []byte) (int64, interface{}) {
+
+	received := new(message)
+	if json.Unmarshal(data, received) != nil {
+		return 0, nil
+	}
+
+	if received.Type == "event" || (received.ID == 0 && received.Method != "") {
+		return 0, &Event{Method: received.Method, Params: received.Params}
+	}
+
+	return received.ID, nil
+
+}
+
+//execute sends a command and decodes its result into result, unless nil
+func (client *Client) execute(ctx context.Context, method string, params interface{}, result interface{}) error {
+
+	data, err := client.Execute(ctx, method, params)
+	if err != nil {
+		return err
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	return json.Unmarshal(data, result)
+
+}
+
+//Events returns a channel receiving the events named method, or every event if method is empty.
+//Events are queued until received, and the channel is closed by the returned function or when the client is closed.
+//The remote end only sends the events subscribed to with Subscribe.
+func (client *Client) Events(method string) (<-chan *Event, func()) {
+
+	subscription := client.rpc.Subscribe(func(event interface{}) bool {
+		return method == "" || event.(*Event).Method == method
+	})
+
+	events := make(chan *Event)
+
+	go func() {
+		defer close(events)
+		for event := range subscription.Events() {
+			select {
+			case events <- event.(*Event):
+			case <-subscription.Done():
+				return
+			}
+		}
+	}()
+
+	return events, func() { client.rpc.Unsubscribe(subscription) }
+
+}
+
+//Close closes the connection, failing the pending commands and closing the event channels
+func (client *Client) Close() error {
+	return client.rpc.Close()
+}
@@ -0,0 +1,76 @@
+package bidi
+
+//Events of the log and network modules
+const (
+	LogEntryAdded            = "log.entryAdded"
+	NetworkBeforeRequestSent = "network.beforeRequestSent"
+	NetworkResponseStarted   = "network.responseStarted"
+	NetworkResponseCompleted = "network.responseCompleted"
+	NetworkFetchError        = "network.fetchError"
+)
+
+//Log levels of LogEntry
+const (
+	LevelDebug = "debug"
+	LevelInfo  = "info"
+	LevelWarn  = "warn"
+	LevelError = "error"
+)
+
+//Source is the realm and browsing context an event comes from
+type Source struct {
+	Realm   string `json:"realm"`
+	Context string `json:"context,omitempty"`
+}
+
+//LogEntry is the parameter of a log.entryAdded event: a console message when Type is "console",
+//or an uncaught exception when Type is "javascript"
+type LogEntry struct {
+	Type      string         `json:"type"`
+	Level     string         `json:"level"`
+	Source    Source         `json:"source"`
+	Text      string         `json:"text"`
+	Timestamp int64          `json:"timestamp"`
+	Method    string         `json:"method,omitempty"`
+	Args      []*RemoteValue `json:"args,omitempty"`
+}
+
+//Header is an HTTP header of a request or a response
+type Header struct {
+	Name  string `json:"name"`
+	Value struct {
+		Type  string `json:"type"`
+		Value string `json:"value"`
+	} `json:"value"`
+}
+
+//RequestData describes the request of a network event
+type RequestData struct {
+	Request string    `json:"request"`
+	URL     string    `json:"url"`
+	Method  string    `json:"method"`
+	Headers []*Header `json:"headers"`
+}
+
+//ResponseData describes the response of a network event
+type ResponseData struct {
+	URL        string    `json:"url"`
+	Protocol   string    `json:"protocol"`
+	Status     int       `json:"status"`
+	StatusText string    `json:"statusText"`
+	FromCache  bool      `json:"fromCache"`
+	MimeType   string    `json:"mimeType"`
+	Headers    []*Header `json:"headers"`
+}
+
+//NetworkEvent is the parameter of the network events. Response is only set by network.responseStarted and
+//network.responseCompleted, ErrorText by network.fetchError.
+type NetworkEvent struct {
+	Context       string        `json:"context"`
+	Navigation    string        `json:"navigation"`
+	RedirectCount int           `json:"redirectCount"`
+	Request       *RequestData  `json:"request"`
+	Response      *ResponseData `json:"response,omitempty"`
+	ErrorText     string        `json:"errorText,omitempty"`
+	Timestamp     int64         `json:"timestamp"`
+}
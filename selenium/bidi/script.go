@@ -0,0 +1,293 @@
+package bidi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+
+	"../../selenium"
+)
+
+//RemoteValue is a JavaScript value serialized by the remote end
+type RemoteValue struct {
+	Type     string          `json:"type"`
+	Value    json.RawMessage `json:"value,omitempty"`
+	Handle   string          `json:"handle,omitempty"`
+	SharedID string          `json:"sharedId,omitempty"`
+}
+
+//Interface converts primitives, arrays and objects to their Go equivalent: nil, bool, float64, string,
+//[]interface{} and map[string]interface{}. Other values, such as nodes, are returned as the *RemoteValue itself.
+func (value *RemoteValue) Interface() (interface{}, error) {
+
+	switch value.Type {
+
+	case "undefined", "null":
+		return nil, nil
+
+	case "string", "boolean", "bigint":
+		var primitive interface{}
+		err := json.Unmarshal(value.Value, &primitive)
+		return primitive, err
+
+	case "number":
+		var number interface{}
+		if err := json.Unmarshal(value.Value, &number); err != nil {
+			return nil, err
+		}
+		//non finite numbers are serialized as strings
+		switch number {
+		case "NaN":
+			return math.NaN(), nil
+		case "-0":
+			return math.Copysign(0, -1), nil
+		case "Infinity":
+			return math.Inf(1), nil
+		case "-Infinity":
+			return math.Inf(-1), nil
+		}
+		return number, nil
+
+	case "array", "set":
+		items := make([]*RemoteValue, 0)
+		if err := json.Unmarshal(value.Value, &items); err != nil {
+			return nil, err
+		}
+		converted := make([]interface{}, len(items))
+		for i, item := range items {
+			var err error
+			if converted[i], err = item.Interface(); err != nil {
+				return nil, err
+			}
+		}
+		return converted, nil
+
+	case "object", "map":
+		entries := make([][2]json.RawMessage, 0)
+		if err := json.Unmarshal(value.Value, &entries); err != nil {
+			return nil, err
+		}
+		converted := make(map[string]interface{}, len(entries))
+		for _, entry := range entries {
+			key, err := decodeKey(entry[0])
+			if err != nil {
+				return nil, err
+			}
+			item := new(RemoteValue)
+			if err := json.Unmarshal(entry[1], item); err != nil {
+				return nil, err
+			}
+			if converted[key], err = item.Interface(); err != nil {
+				return nil, err
+			}
+		}
+		return converted, nil
+
+	}
+
+	return value, nil
+
+}
+
+//decodeKey decodes the key of an object or map entry, a string or a serialized value
+func decodeKey(data json.RawMessage) (string, error) {
+
+	var key string
+	if json.Unmarshal(data, &key) == nil {
+		return key, nil
+	}
+
+	value := new(RemoteValue)
+	if err := json.Unmarshal(data, value); err != nil {
+		return "", err
+	}
+
+	converted, err := value.Interface()
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprint(converted), nil
+
+}
+
+//LocalValue serializes a Go value as a BiDi argument. Elements found with WebDriver and remote values with a handle
+//or a shared ID are passed by reference.
+func LocalValue(v interface{}) interface{} {
+
+	switch value := v.(type) {
+
+	case nil:
+		return map[string]interface{}{"type": "null"}
+
+	case string:
+		return map[string]interface{}{"type": "string", "value": value}
+
+	case bool:
+		return map[string]interface{}{"type": "boolean", "value": value}
+
+	case selenium.WebElementInfo:
+		return map[string]interface{}{"sharedId": value.GetValue()}
+
+	case *RemoteValue:
+		if value.SharedID != "" {
+			return map[string]interface{}{"sharedId": value.SharedID}
+		}
+		return map[string]interface{}{"handle": value.Handle}
+
+	case []interface{}:
+		items := make([]interface{}, len(value))
+		for i, item := range value {
+			items[i] = LocalValue(item)
+		}
+		return map[string]interface{}{"type": "array", "value": items}
+
+	case map[string]interface{}:
+		entries := make([]interface{}, 0, len(value))
+		for key, item := range value {
+			entries = append(entries, []interface{}{key, LocalValue(item)})
+		}
+		return map[string]interface{}{"type": "object", "value": entries}
+
+	}
+
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "number", "value": v}
+	case reflect.Float32, reflect.Float64:
+		if special, ok := specialNumber(reflect.ValueOf(v).Float()); ok {
+			return map[string]interface{}{"type": "number", "value": special}
+		}
+		return map[string]interface{}{"type": "number", "value": v}
+	}
+
+	//other values, e.g. structs and typed slices, are serialized through their JSON encoding
+	var decoded interface{}
+	data, err := json.Marshal(v)
+	if err == nil && json.Unmarshal(data, &decoded) == nil {
+		return LocalValue(decoded)
+	}
+
+	return map[string]interface{}{"type": "undefined"}
+
+}
+
+//specialNumber returns the string serializing number when JSON cannot represent it, i.e. NaN, infinities and -0
+func specialNumber(number float64) (string, bool) {
+
+	switch {
+	case math.IsNaN(number):
+		return "NaN", true
+	case math.IsInf(number, 1):
+		return "Infinity", true
+	case math.IsInf(number, -1):
+		return "-Infinity", true
+	case number == 0 && math.Signbit(number):
+		return "-0", true
+	}
+
+	return "", false
+
+}
+
+//ScriptException is returned when a script throws
+type ScriptException struct {
+	Text         string `json:"text"`
+	LineNumber   int    `json:"lineNumber"`
+	ColumnNumber int    `json:"columnNumber"`
+}
+
+func (e *ScriptException) Error() string {
+	return fmt.Sprintf("script exception at %d:%d: %s", e.LineNumber, e.ColumnNumber, e.Text)
+}
+
+type evaluateResult struct {
+	Type             string           `json:"type"`
+	Result           *RemoteValue     `json:"result"`
+	ExceptionDetails *ScriptException `json:"exceptionDetails"`
+}
+
+func (result *evaluateResult) value() (*RemoteValue, error) {
+
+	if result.Type == "exception" {
+		if result.ExceptionDetails == nil {
+			return nil, &ScriptException{Text: "unknown exception"}
+		}
+		return nil, result.ExceptionDetails
+	}
+
+	return result.Result, nil
+
+}
+
+//Evaluate evaluates expression in the browsing context, waiting for the returned promise to settle if awaitPromise is true
+func (client *Client) Evaluate(ctx context.Context, browsingContext string, expression string, awaitPromise bool) (*RemoteValue, error) {
+
+	result := new(evaluateResult)
+
+	err := client.execute(ctx, "script.evaluate", map[string]interface{}{
+		"expression":   expression,
+		"target":       map[string]interface{}{"context": browsingContext},
+		"awaitPromise": awaitPromise,
+	}, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.value()
+
+}
+
+//CallFunction calls functionDeclaration, e.g. "(a, b) => a + b", in the browsing context with args serialized by LocalValue
+func (client *Client) CallFunction(ctx context.Context, browsingContext string, functionDeclaration string, awaitPromise bool, args ...interface{}) (*RemoteValue, error) {
+
+	arguments := make([]interface{}, len(args))
+	for i, arg := range args {
+		arguments[i] = LocalValue(arg)
+	}
+
+	result := new(evaluateResult)
+
+	err := client.execute(ctx, "script.callFunction", map[string]interface{}{
+		"functionDeclaration": functionDeclaration,
+		"target":              map[string]interface{}{"context": browsingContext},
+		"arguments":           arguments,
+		"awaitPromise":        awaitPromise,
+	}, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result.value()
+
+}
+
+//AddPreloadScript runs functionDeclaration in every new document of the browsing contexts, or of every context when none
+//is given, before its own scripts. It returns the ID of the preload script.
+func (client *Client) AddPreloadScript(ctx context.Context, functionDeclaration string, contexts ...string) (string, error) {
+
+	params := map[string]interface{}{"functionDeclaration": functionDeclaration}
+	if len(contexts) > 0 {
+		params["contexts"] = contexts
+	}
+
+	result := struct {
+		Script string `json:"script"`
+	}{}
+
+	err := client.execute(ctx, "script.addPreloadScript", params, &result)
+	if err != nil {
+		return "", err
+	}
+
+	return result.Script, nil
+
+}
+
+//RemovePreloadScript removes a preload script added by AddPreloadScript
+func (client *Client) RemovePreloadScript(ctx context.Context, script string) error {
+	return client.execute(ctx, "script.removePreloadScript", map[string]interface{}{"script": script}, nil)
+}
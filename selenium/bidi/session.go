@@ -0,0 +1,28 @@
+package bidi
+
+import "context"
+
+//Subscribe asks the remote end to send the events, e.g. "log.entryAdded" or a whole module such as "network",
+//for the browsing contexts, or every context when none is given. The events are received with Events.
+func (client *Client) Subscribe(ctx context.Context, events []string, contexts ...string) error {
+
+	params := map[string]interface{}{"events": events}
+	if len(contexts) > 0 {
+		params["contexts"] = contexts
+	}
+
+	return client.execute(ctx, "session.subscribe", params, nil)
+
+}
+
+//Unsubscribe asks the remote end to stop sending the events subscribed to with Subscribe
+func (client *Client) Unsubscribe(ctx context.Context, events []string, contexts ...string) error {
+
+	params := map[string]interface{}{"events": events}
+	if len(contexts) > 0 {
+		params["contexts"] = contexts
+	}
+
+	return client.execute(ctx, "session.unsubscribe", params, nil)
+
+}
@@ -0,0 +1,167 @@
+package selenium
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//Driver is a WebDriver running its own local driver server, as returned by Start
+type Driver interface {
+	WebDriver
+	Quit() error
+}
+
+//Options configures a browser independently of its driver. Each browser package provides an implementation.
+type Options interface {
+	SetHeadless(headless bool)
+	SetWindowSize(width int, height int)
+	AddArgs(args ...string)
+	AddPref(name string, value interface{})
+	SetBinary(binary string)
+	SetProxy(proxy *Proxy)
+	SetAcceptInsecureCerts(accept bool)
+	SetWebSocketURL(enabled bool)
+}
+
+//BrowserOptions holds the W3C capabilities set through the Options interface, as opposed to the browser specific options.
+//Browser packages embed it in their options and apply it to the capabilities of the session.
+type BrowserOptions struct {
+	proxy               *Proxy
+	acceptInsecureCerts bool
+	webSocketURL        bool
+}
+
+//SetProxy sets the proxy capability of the session
+func (options *BrowserOptions) SetProxy(proxy *Proxy) {
+	options.proxy = proxy
+}
+
+//SetAcceptInsecureCerts sets whether the session trusts untrusted or self-signed TLS certificates
+func (options *BrowserOptions) SetAcceptInsecureCerts(accept bool) {
+	options.acceptInsecureCerts = accept
+}
+
+//SetWebSocketURL requests a WebDriver BiDi connection to the browser, see the bidi package
+func (options *BrowserOptions) SetWebSocketURL(enabled bool) {
+	options.webSocketURL = enabled
+}
+
+//Apply sets the capabilities held by options on caps
+func (options *BrowserOptions) Apply(caps Capabilities) {
+
+	if options.proxy != nil {
+		caps.SetProxy(options.proxy)
+	}
+	caps.SetAcceptInsecureCerts(options.acceptInsecureCerts)
+	caps.SetWebSocketURL(options.webSocketURL)
+
+}
+
+//RemoveArg returns args without the switch name, given either as name=value or as name followed by the number of values
+func RemoveArg(args []string, name string, values int) []string {
+
+	kept := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		if args[i] == name {
+			i += values
+			continue
+		}
+		if strings.HasPrefix(args[i], name+"=") {
+			continue
+		}
+		kept = append(kept, args[i])
+	}
+
+	return kept
+
+}
+
+//Browser is registered by a browser package to be started by name
+type Browser struct {
+	//NewOptions returns empty options of the browser
+	NewOptions func() Options
+	//Start starts the driver server and a session with options, which were returned by NewOptions,
+	//sending every command, including the new session, through middlewares
+	Start func(options Options, middlewares ...Middleware) (Driver, error)
+}
+
+var (
+	browsersMutex sync.RWMutex
+	browsers      = make(map[string]Browser)
+)
+
+//RegisterBrowser makes a browser available to Start under name. It is called from the init function of browser packages,
+//so importing e.g. the chrome package for its side effects is enough to start "chrome".
+func RegisterBrowser(name string, browser Browser) {
+
+	browsersMutex.Lock()
+	defer browsersMutex.Unlock()
+
+	if _, ok := browsers[name]; ok {
+		panic("selenium: browser registered twice: " + name)
+	}
+
+	browsers[name] = browser
+
+}
+
+//Browsers returns the sorted names of the registered browsers
+func Browsers() []string {
+
+	browsersMutex.RLock()
+	defer browsersMutex.RUnlock()
+
+	names := make([]string, 0, len(browsers))
+	for name := range browsers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+
+}
+
+func lookupBrowser(name string) (Browser, error) {
+
+	browsersMutex.RLock()
+	browser, ok := browsers[name]
+	browsersMutex.RUnlock()
+
+	if !ok {
+		return Browser{}, fmt.Errorf("unknown browser %q (forgotten import?), registered browsers: %v", name, Browsers())
+	}
+
+	return browser, nil
+
+}
+
+//NewOptions returns empty options of the named browser
+func NewOptions(browserName string) (Options, error) {
+
+	browser, err := lookupBrowser(browserName)
+	if err != nil {
+		return nil, err
+	}
+
+	return browser.NewOptions(), nil
+
+}
+
+//Start starts the named browser with options, which may be nil, and returns the driver of its session.
+//Middlewares are used before the session is created, so they also see the "New Session" command.
+func Start(browserName string, options Options, middlewares ...Middleware) (Driver, error) {
+
+	browser, err := lookupBrowser(browserName)
+	if err != nil {
+		return nil, err
+	}
+
+	if options == nil {
+		options = browser.NewOptions()
+	}
+
+	return browser.Start(options, middlewares...)
+
+}
@@ -0,0 +1,84 @@
+package selenium
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type fakeOptions struct {
+	headless bool
+}
+
+func (options *fakeOptions) SetHeadless(headless bool)              { options.headless = headless }
+func (options *fakeOptions) SetWindowSize(width int, height int)    {}
+func (options *fakeOptions) AddArgs(args ...string)                 {}
+func (options *fakeOptions) AddPref(name string, value interface{}) {}
+func (options *fakeOptions) SetBinary(binary string)                {}
+func (options *fakeOptions) SetProxy(proxy *Proxy)                  {}
+func (options *fakeOptions) SetAcceptInsecureCerts(accept bool)     {}
+func (options *fakeOptions) SetWebSocketURL(enabled bool)           {}
+
+type fakeDriver struct {
+	WebDriver
+	options     *fakeOptions
+	middlewares []Middleware
+}
+
+func (driver *fakeDriver) Quit() error { return nil }
+
+func TestStart(t *testing.T) {
+
+	RegisterBrowser("fake", Browser{
+		NewOptions: func() Options { return new(fakeOptions) },
+		Start: func(options Options, middlewares ...Middleware) (Driver, error) {
+			return &fakeDriver{options: options.(*fakeOptions), middlewares: middlewares}, nil
+		},
+	})
+
+	require.Containsf(t, Browsers(), "fake", "Registered browser should be listed.")
+
+	options, err := NewOptions("fake")
+	require.NoErrorf(t, err, "Options of a registered browser should be returned.")
+	options.SetHeadless(true)
+
+	driver, err := Start("fake", options)
+	require.NoErrorf(t, err, "Registered browser should be started.")
+	require.Truef(t, driver.(*fakeDriver).options.headless, "Options should be passed to the browser.")
+
+	driver, err = Start("fake", nil, NewLatencyStats().Middleware())
+	require.NoErrorf(t, err, "Browser should be started with default options.")
+	require.Lenf(t, driver.(*fakeDriver).middlewares, 1, "Middlewares should be passed to the browser.")
+	require.NoErrorf(t, driver.Quit(), "Driver should quit.")
+
+	_, err = Start("netscape", nil)
+	require.Errorf(t, err, "Unknown browsers should not be started.")
+
+}
+
+func TestBrowserOptions(t *testing.T) {
+
+	options := new(BrowserOptions)
+	options.SetProxy(&Proxy{ProxyType: Manual, HTTPProxy: "127.0.0.1:3128"})
+	options.SetAcceptInsecureCerts(true)
+	options.SetWebSocketURL(true)
+
+	caps := NewCapabilities()
+	options.Apply(caps)
+
+	encoded, err := MarshalCapabilities(caps, nil)
+	require.NoErrorf(t, err, "Capabilities should be encoded.")
+	require.Containsf(t, string(encoded), `"acceptInsecureCerts":true`, "Insecure certificates should be accepted.")
+	require.Containsf(t, string(encoded), `"webSocketUrl":true`, "BiDi connection should be requested.")
+	require.Containsf(t, string(encoded), "127.0.0.1:3128", "Proxy should be set.")
+
+}
+
+func TestRemoveArg(t *testing.T) {
+
+	args := []string{"--headless=new", "-width", "800", "--window-size=1,2", "--headless", "-height", "600"}
+
+	require.Equalf(t, []string{"-width", "800", "--window-size=1,2", "-height", "600"}, RemoveArg(args, "--headless", 0), "Switch should be removed with or without a value.")
+	require.Equalf(t, []string{"--headless=new", "--window-size=1,2", "--headless", "-height", "600"}, RemoveArg(args, "-width", 1), "Switch should be removed with the values following it.")
+
+}
@@ -1,5 +1,7 @@
 package selenium
 
+import "encoding/json"
+
 //Capabilities - Selenium capabilities
 type capabilities struct {
 	BrowserName             string            `json:"browserName,omitempty"`
@@ -11,6 +13,7 @@ type capabilities struct {
 	WindowRect              bool              `json:"setWindowRect,omitempty"`
 	Timeouts                *Timeouts         `json:"timeouts,omitempty"`
 	UnhandledPromptBehavior string            `json:"unhandledPromptBehavior,omitempty"`
+	WebSocketURL            bool              `json:"webSocketUrl,omitempty"`
 }
 
 //Capabilities provides an inteface to common W3C driver capabilities
@@ -24,6 +27,7 @@ type Capabilities interface {
 	SetWindowRect(wr bool)
 	SetTimeouts(timeouts *Timeouts)
 	SetUnhandledPromptBehavior(uhp string)
+	SetWebSocketURL(enabled bool)
 }
 
 //NewCapabilities returns an implementation of the Capabilities interface
@@ -75,3 +79,35 @@ func (caps *capabilities) SetTimeouts(timeouts *Timeouts) {
 func (caps *capabilities) SetUnhandledPromptBehavior(uhp string) {
 	caps.UnhandledPromptBehavior = uhp
 }
+
+//SetWebSocketURL requests a WebDriver BiDi connection, whose URL is returned in the webSocketUrl capability of the session.
+func (caps *capabilities) SetWebSocketURL(enabled bool) {
+	caps.WebSocketURL = enabled
+}
+
+//MarshalCapabilities encodes caps as a single JSON object extended with the browser specific capabilities, e.g. "goog:chromeOptions"
+func MarshalCapabilities(caps Capabilities, extensions map[string]interface{}) ([]byte, error) {
+
+	merged := make(map[string]interface{})
+
+	if caps != nil {
+
+		data, err := json.Marshal(caps)
+		if err != nil {
+			return nil, err
+		}
+
+		err = json.Unmarshal(data, &merged)
+		if err != nil {
+			return nil, err
+		}
+
+	}
+
+	for name, value := range extensions {
+		merged[name] = value
+	}
+
+	return json.Marshal(merged)
+
+}
@@ -0,0 +1,18 @@
+package selenium
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalCapabilities(t *testing.T) {
+
+	caps := NewCapabilities()
+	caps.SetBrowserName("chrome")
+
+	data, err := MarshalCapabilities(caps, map[string]interface{}{"goog:chromeOptions": map[string]interface{}{"args": []string{"--headless=new"}}})
+	require.NoErrorf(t, err, "Capabilities should be encoded.")
+	require.JSONEqf(t, `{"browserName": "chrome", "goog:chromeOptions": {"args": ["--headless=new"]}}`, string(data), "Browser specific capabilities should be merged.")
+
+}
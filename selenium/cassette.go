@@ -0,0 +1,239 @@
+package selenium
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"sync"
+)
+
+//ErrNoInteraction is returned by a ReplayExecutor when no recorded interaction matches a command
+var ErrNoInteraction = errors.New("no recorded interaction matches command")
+
+//Interaction is one command and its reply as recorded in a cassette
+type Interaction struct {
+	Method     Method                 `json:"method"`
+	Endpoint   string                 `json:"endpoint"`
+	Request    interface{}            `json:"request,omitempty"`
+	StatusCode int                    `json:"statusCode,omitempty"`
+	Response   map[string]interface{} `json:"response,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+}
+
+//Cassette holds the WebDriver traffic of a session in the order it was sent
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+//LoadCassette reads a cassette from the named JSON file
+func LoadCassette(path string) (*Cassette, error) {
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cassette := new(Cassette)
+	err = json.Unmarshal(data, cassette)
+	if err != nil {
+		return nil, err
+	}
+
+	return cassette, nil
+
+}
+
+//Save writes the cassette to the named JSON file
+func (cassette *Cassette) Save(path string) error {
+
+	data, err := json.MarshalIndent(cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, data, 0644)
+
+}
+
+//Recorder captures every command passing through its middleware into a cassette
+type Recorder struct {
+	mutex    sync.Mutex
+	cassette *Cassette
+}
+
+//NewRecorder returns a recorder with an empty cassette
+func NewRecorder() *Recorder {
+	return &Recorder{cassette: &Cassette{Interactions: make([]*Interaction, 0)}}
+}
+
+//Middleware returns the middleware recording every command and its reply
+func (recorder *Recorder) Middleware() Middleware {
+
+	return func(next CommandExecutor) CommandExecutor {
+		return CommandExecutorFunc(func(ctx context.Context, method Method, endpoint string, data interface{}) (*Reply, error) {
+
+			reply, err := next.Execute(ctx, method, endpoint, data)
+
+			request, marshalErr := normalizeRequest(data)
+			if marshalErr != nil {
+				return reply, err
+			}
+
+			interaction := &Interaction{Method: method, Endpoint: endpoint, Request: request}
+
+			if err != nil {
+				interaction.Error = err.Error()
+			} else {
+				interaction.StatusCode = reply.StatusCode
+				interaction.Response, marshalErr = copyReplyData(reply.Data)
+				if marshalErr != nil {
+					return reply, err
+				}
+			}
+
+			recorder.mutex.Lock()
+			recorder.cassette.Interactions = append(recorder.cassette.Interactions, interaction)
+			recorder.mutex.Unlock()
+
+			return reply, err
+
+		})
+	}
+
+}
+
+//Cassette returns a copy of the cassette recorded so far
+func (recorder *Recorder) Cassette() *Cassette {
+
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	interactions := make([]*Interaction, len(recorder.cassette.Interactions))
+	copy(interactions, recorder.cassette.Interactions)
+
+	return &Cassette{Interactions: interactions}
+
+}
+
+//Save writes the cassette recorded so far to the named JSON file
+func (recorder *Recorder) Save(path string) error {
+	return recorder.Cassette().Save(path)
+}
+
+//ReplayExecutor is a CommandExecutor answering commands with the replies recorded in a cassette, without a remote end.
+//A command is matched against the recorded interactions with the same method, endpoint and payload, in recorded order.
+type ReplayExecutor struct {
+	mutex        sync.Mutex
+	interactions map[string][]*Interaction
+	remaining    int
+}
+
+//NewReplayExecutor returns a CommandExecutor replaying cassette
+func NewReplayExecutor(cassette *Cassette) *ReplayExecutor {
+
+	executor := &ReplayExecutor{interactions: make(map[string][]*Interaction)}
+
+	for _, interaction := range cassette.Interactions {
+		key := interactionKey(interaction.Method, interaction.Endpoint, interaction.Request)
+		executor.interactions[key] = append(executor.interactions[key], interaction)
+		executor.remaining++
+	}
+
+	return executor
+
+}
+
+//Execute returns the next recorded reply to the command, or an error wrapping ErrNoInteraction
+func (executor *ReplayExecutor) Execute(ctx context.Context, method Method, endpoint string, data interface{}) (*Reply, error) {
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	request, err := normalizeRequest(data)
+	if err != nil {
+		return nil, err
+	}
+
+	key := interactionKey(method, endpoint, request)
+
+	executor.mutex.Lock()
+	defer executor.mutex.Unlock()
+
+	queue := executor.interactions[key]
+	if len(queue) == 0 {
+		return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, method, endpoint)
+	}
+
+	interaction := queue[0]
+	executor.interactions[key] = queue[1:]
+	executor.remaining--
+
+	if interaction.Error != "" {
+		return nil, errors.New(interaction.Error)
+	}
+
+	return &Reply{StatusCode: interaction.StatusCode, Data: interaction.Response, Method: method, Endpoint: endpoint}, nil
+
+}
+
+//Remaining returns the number of recorded interactions not replayed yet
+func (executor *ReplayExecutor) Remaining() int {
+
+	executor.mutex.Lock()
+	defer executor.mutex.Unlock()
+
+	return executor.remaining
+
+}
+
+//normalizeRequest converts a command payload to its generic JSON form, as it is read back from a cassette
+func normalizeRequest(data interface{}) (interface{}, error) {
+
+	if data == nil {
+		return nil, nil
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var request interface{}
+	err = json.Unmarshal(encoded, &request)
+	if err != nil {
+		return nil, err
+	}
+
+	return request, nil
+
+}
+
+//copyReplyData returns a deep copy of the data of a reply, so that the recording is not altered when the reply is decoded
+func copyReplyData(data map[string]interface{}) (map[string]interface{}, error) {
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var copied map[string]interface{}
+	err = json.Unmarshal(encoded, &copied)
+	if err != nil {
+		return nil, err
+	}
+
+	return copied, nil
+
+}
+
+func interactionKey(method Method, endpoint string, request interface{}) string {
+
+	//encoding/json sorts map keys, so equal payloads give equal keys
+	encoded, _ := json.Marshal(request)
+
+	return string(method) + " " + endpoint + " " + string(encoded)
+
+}
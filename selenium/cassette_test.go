@@ -0,0 +1,97 @@
+package selenium
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+
+	titles := []string{"First", "Second"}
+
+	live := CommandExecutorFunc(func(ctx context.Context, method Method, endpoint string, data interface{}) (*Reply, error) {
+		if method != GET {
+			return &Reply{StatusCode: 200, Data: map[string]interface{}{"value": nil}}, nil
+		}
+		title := titles[0]
+		titles = titles[1:]
+		return &Reply{StatusCode: 200, Data: map[string]interface{}{"value": title}}, nil
+	})
+
+	recorder := NewRecorder()
+
+	wd := NewRemoteWithExecutor(live, NewCapabilities())
+	wd.SetSession("1", nil)
+	wd.Use(recorder.Middleware())
+
+	require.NoErrorf(t, wd.Navigate("http://example.test/"), "Navigate should be recorded.")
+
+	first, err := wd.GetTitle()
+	require.NoErrorf(t, err, "GetTitle should succeed.")
+
+	second, err := wd.GetTitle()
+	require.NoErrorf(t, err, "GetTitle should succeed.")
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	require.NoErrorf(t, recorder.Save(path), "Cassette should be saved.")
+
+	cassette, err := LoadCassette(path)
+	require.NoErrorf(t, err, "Cassette should be loaded.")
+	require.Lenf(t, cassette.Interactions, 3, "Every command should be recorded.")
+
+	replay := NewReplayExecutor(cassette)
+
+	wd = NewRemoteWithExecutor(replay, NewCapabilities())
+	wd.SetSession("1", nil)
+
+	require.NoErrorf(t, wd.Navigate("http://example.test/"), "Recorded command should be replayed.")
+
+	title, err := wd.GetTitle()
+	require.NoErrorf(t, err, "GetTitle should be replayed.")
+	require.Equalf(t, first, title, "Replies should be replayed in recorded order.")
+
+	title, err = wd.GetTitle()
+	require.NoErrorf(t, err, "GetTitle should be replayed.")
+	require.Equalf(t, second, title, "Replies should be replayed in recorded order.")
+	require.Equalf(t, 0, replay.Remaining(), "Every interaction should be replayed.")
+
+	err = wd.Navigate("http://other.test/")
+	require.Truef(t, errors.Is(err, ErrNoInteraction), "Commands with a different payload should not match.")
+
+}
+
+func TestRecordScriptResult(t *testing.T) {
+
+	live := CommandExecutorFunc(func(ctx context.Context, method Method, endpoint string, data interface{}) (*Reply, error) {
+		element := map[string]interface{}{WebElementIdentifier: "a"}
+		return &Reply{StatusCode: 200, Data: map[string]interface{}{"value": element}}, nil
+	})
+
+	//clears the reply once recorded, as a middleware holding on to it could
+	strip := func(next CommandExecutor) CommandExecutor {
+		return CommandExecutorFunc(func(ctx context.Context, method Method, endpoint string, data interface{}) (*Reply, error) {
+			reply, err := next.Execute(ctx, method, endpoint, data)
+			element := reply.Data["value"].(map[string]interface{})
+			delete(element, WebElementIdentifier)
+			return reply, err
+		})
+	}
+
+	recorder := NewRecorder()
+
+	wd := NewRemoteWithExecutor(live, NewCapabilities())
+	wd.SetSession("1", nil)
+	wd.Use(strip, recorder.Middleware())
+
+	_, err := wd.ExecuteScript("return document.body")
+	require.NoErrorf(t, err, "ExecuteScript should succeed.")
+
+	cassette := recorder.Cassette()
+	require.Lenf(t, cassette.Interactions, 1, "Script should be recorded.")
+	require.Equalf(t, map[string]interface{}{WebElementIdentifier: "a"}, cassette.Interactions[0].Response["value"], "Recorded reply should not change with the reply.")
+
+}
@@ -0,0 +1,31 @@
+package chrome
+
+import (
+	"errors"
+
+	"../../selenium"
+)
+
+func init() {
+	selenium.RegisterBrowser("chrome", selenium.Browser{
+		NewOptions: func() selenium.Options { return new(ChromeOptions) },
+		Start:      start,
+	})
+}
+
+//start starts chromedriver, found from the CHROMEDRIVER environment variable or $PATH, on a free port
+func start(options selenium.Options, middlewares ...selenium.Middleware) (selenium.Driver, error) {
+
+	chromeOptions, ok := options.(*ChromeOptions)
+	if !ok {
+		return nil, errors.New("chrome requires *chrome.ChromeOptions")
+	}
+
+	driver, err := Driver("", 0, chromeOptions, middlewares...)
+	if err != nil {
+		return nil, err
+	}
+
+	return driver, nil
+
+}
@@ -4,5 +4,39 @@ import "../../selenium"
 
 type Capabilities struct {
 	selenium.Capabilities
-	ChromeOptions *ChromeOptions `json:"chromeOptions,omitempty"`
+	ChromeOptions *ChromeOptions `json:"goog:chromeOptions,omitempty"`
+
+	optionsKey string
+}
+
+//MarshalJSON encodes the W3C capabilities and the chrome options as a single object
+func (caps *Capabilities) MarshalJSON() ([]byte, error) {
+
+	key := caps.optionsKey
+	if key == "" {
+		key = "goog:chromeOptions"
+	}
+
+	extensions := make(map[string]interface{})
+	if caps.ChromeOptions != nil {
+		extensions[key] = caps.ChromeOptions
+	}
+
+	return selenium.MarshalCapabilities(caps.Capabilities, extensions)
+
+}
+
+//NewChromiumCapabilities returns the capabilities of a session of a Chromium based browser, sending options under optionsKey,
+//e.g. "goog:chromeOptions" for Chrome or "ms:edgeOptions" for Edge
+func NewChromiumCapabilities(browserName string, optionsKey string, options *ChromeOptions) *Capabilities {
+
+	caps := &Capabilities{Capabilities: selenium.NewCapabilities(), ChromeOptions: options, optionsKey: optionsKey}
+	caps.SetBrowserName(browserName)
+
+	if options != nil {
+		options.Apply(caps)
+	}
+
+	return caps
+
 }
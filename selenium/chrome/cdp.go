@@ -0,0 +1,248 @@
+package chrome
+
+import (
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"../../selenium"
+	"../errors"
+)
+
+//ExecuteCDPCommand sends the Chrome DevTools Protocol command method, e.g. "Network.clearBrowserCache", with params
+//through the driver and returns the decoded result of the command
+func (driver *chromeDriver) ExecuteCDPCommand(method string, params map[string]interface{}) (map[string]interface{}, error) {
+
+	info, ok := driver.WebDriver.(selenium.WebDriverInfo)
+	if !ok {
+		return nil, errors.WebDriverInfoNotImplemented()
+	}
+
+	if params == nil {
+		params = make(map[string]interface{})
+	}
+
+	reply, err := selenium.ExecuteSessionCommand(
+		info,
+		selenium.POST,
+		fmt.Sprintf("/session/%s/%s/cdp/execute", info.GetSession().GetID(), driver.vendor),
+		map[string]interface{}{"cmd": method, "params": params},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if reply.StatusCode != 200 {
+		return nil, reply.GetError()
+	}
+
+	value, err := reply.Get("value", false)
+	if err != nil {
+		return nil, err
+	}
+
+	switch result := value.(type) {
+	case nil:
+		//commands without a result may return a null value
+		return make(map[string]interface{}), nil
+	case map[string]interface{}:
+		return result, nil
+	}
+
+	return nil, fmt.Errorf("%s returned an unexpected result: %v", method, value)
+
+}
+
+//SetDeviceMetricsOverride emulates the screen of a device, e.g. a mobile phone, with Emulation.setDeviceMetricsOverride
+func (driver *chromeDriver) SetDeviceMetricsOverride(width int, height int, deviceScaleFactor float64, mobile bool) error {
+
+	_, err := driver.ExecuteCDPCommand("Emulation.setDeviceMetricsOverride", map[string]interface{}{
+		"width":             width,
+		"height":            height,
+		"deviceScaleFactor": deviceScaleFactor,
+		"mobile":            mobile,
+	})
+
+	return err
+
+}
+
+//ClearDeviceMetricsOverride restores the screen of the browser
+func (driver *chromeDriver) ClearDeviceMetricsOverride() error {
+
+	_, err := driver.ExecuteCDPCommand("Emulation.clearDeviceMetricsOverride", nil)
+
+	return err
+
+}
+
+//SetGeolocationOverride sets the position returned by the Geolocation API, with an accuracy in meters
+func (driver *chromeDriver) SetGeolocationOverride(latitude float64, longitude float64, accuracy float64) error {
+
+	_, err := driver.ExecuteCDPCommand("Emulation.setGeolocationOverride", map[string]interface{}{
+		"latitude":  latitude,
+		"longitude": longitude,
+		"accuracy":  accuracy,
+	})
+
+	return err
+
+}
+
+//SetTimezoneOverride sets the time zone of the pages, given as an IANA time zone ID such as "Europe/Paris"
+func (driver *chromeDriver) SetTimezoneOverride(timezoneID string) error {
+
+	_, err := driver.ExecuteCDPCommand("Emulation.setTimezoneOverride", map[string]interface{}{"timezoneId": timezoneID})
+
+	return err
+
+}
+
+//SetUserAgentOverride sets the user agent sent with the requests and returned by navigator.userAgent
+func (driver *chromeDriver) SetUserAgentOverride(userAgent string) error {
+
+	_, err := driver.ExecuteCDPCommand("Emulation.setUserAgentOverride", map[string]interface{}{"userAgent": userAgent})
+
+	return err
+
+}
+
+//SetExtraHTTPHeaders adds headers to every request sent by the pages
+func (driver *chromeDriver) SetExtraHTTPHeaders(headers map[string]string) error {
+
+	_, err := driver.ExecuteCDPCommand("Network.enable", nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = driver.ExecuteCDPCommand("Network.setExtraHTTPHeaders", map[string]interface{}{"headers": headers})
+
+	return err
+
+}
+
+//SetBlockedURLs blocks the requests matching the URL patterns, which may contain '*' wildcards
+func (driver *chromeDriver) SetBlockedURLs(urls ...string) error {
+
+	_, err := driver.ExecuteCDPCommand("Network.enable", nil)
+	if err != nil {
+		return err
+	}
+
+	if urls == nil {
+		urls = make([]string, 0)
+	}
+
+	_, err = driver.ExecuteCDPCommand("Network.setBlockedURLs", map[string]interface{}{"urls": urls})
+
+	return err
+
+}
+
+//NetworkConditions are the emulated network conditions of the browser
+type NetworkConditions struct {
+	Offline bool
+	//Latency is added to every request
+	Latency time.Duration
+	//DownloadThroughput and UploadThroughput are in bytes per second, 0 disables throttling
+	DownloadThroughput int
+	UploadThroughput   int
+}
+
+//EmulateNetworkConditions throttles or disconnects the network of the browser
+func (driver *chromeDriver) EmulateNetworkConditions(conditions *NetworkConditions) error {
+
+	_, err := driver.ExecuteCDPCommand("Network.enable", nil)
+	if err != nil {
+		return err
+	}
+
+	//the protocol disables throttling with -1
+	throughput := func(bytes int) int {
+		if bytes <= 0 {
+			return -1
+		}
+		return bytes
+	}
+
+	_, err = driver.ExecuteCDPCommand("Network.emulateNetworkConditions", map[string]interface{}{
+		"offline":            conditions.Offline,
+		"latency":            conditions.Latency.Milliseconds(),
+		"downloadThroughput": throughput(conditions.DownloadThroughput),
+		"uploadThroughput":   throughput(conditions.UploadThroughput),
+	})
+
+	return err
+
+}
+
+//ClearBrowserCache clears the HTTP cache of the browser
+func (driver *chromeDriver) ClearBrowserCache() error {
+
+	_, err := driver.ExecuteCDPCommand("Network.clearBrowserCache", nil)
+
+	return err
+
+}
+
+//ClearBrowserCookies clears the cookies of every domain, unlike DeleteAllCookies which is limited to the current page
+func (driver *chromeDriver) ClearBrowserCookies() error {
+
+	_, err := driver.ExecuteCDPCommand("Network.clearBrowserCookies", nil)
+
+	return err
+
+}
+
+//AddScriptToEvaluateOnNewDocument evaluates source in every new document before its own scripts,
+//and returns the identifier of the script
+func (driver *chromeDriver) AddScriptToEvaluateOnNewDocument(source string) (string, error) {
+
+	result, err := driver.ExecuteCDPCommand("Page.addScriptToEvaluateOnNewDocument", map[string]interface{}{"source": source})
+	if err != nil {
+		return "", err
+	}
+
+	identifier, ok := result["identifier"].(string)
+	if !ok {
+		return "", fmt.Errorf("Page.addScriptToEvaluateOnNewDocument returned no identifier: %v", result)
+	}
+
+	return identifier, nil
+
+}
+
+//RemoveScriptToEvaluateOnNewDocument removes a script added by AddScriptToEvaluateOnNewDocument
+func (driver *chromeDriver) RemoveScriptToEvaluateOnNewDocument(identifier string) error {
+
+	_, err := driver.ExecuteCDPCommand("Page.removeScriptToEvaluateOnNewDocument", map[string]interface{}{"identifier": identifier})
+
+	return err
+
+}
+
+//CaptureFullPageScreenshot takes a screenshot of the whole page, beyond the viewport
+func (driver *chromeDriver) CaptureFullPageScreenshot() (selenium.Screenshot, error) {
+
+	result, err := driver.ExecuteCDPCommand("Page.captureScreenshot", map[string]interface{}{
+		"format":                "png",
+		"captureBeyondViewport": true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	encoded, ok := result["data"].(string)
+	if !ok {
+		return nil, fmt.Errorf("Page.captureScreenshot returned no data")
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	return selenium.Screenshot(data), nil
+
+}
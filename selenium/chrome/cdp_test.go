@@ -0,0 +1,100 @@
+package chrome
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"../../selenium"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteCDPCommand(t *testing.T) {
+
+	var commands []map[string]interface{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		require.Equalf(t, "/session/session-id/ms/cdp/execute", r.URL.Path, "Command should be sent to the vendor endpoint.")
+
+		command := make(map[string]interface{})
+		require.NoErrorf(t, json.NewDecoder(r.Body).Decode(&command), "Command should be JSON.")
+		commands = append(commands, command)
+
+		switch command["cmd"] {
+		case "Page.addScriptToEvaluateOnNewDocument":
+			w.Write([]byte(`{"value": {"identifier": "1"}}`))
+		case "Browser.crash":
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"value": {"error": "unknown error", "message": "unknown command"}}`))
+		case "Browser.getVersion":
+			w.Write([]byte(`{"value": "Chrome/120"}`))
+		case "Network.clearBrowserCache":
+			w.Write([]byte(`{"value": null}`))
+		default:
+			w.Write([]byte(`{"value": {}}`))
+		}
+
+	}))
+	defer server.Close()
+
+	remote := selenium.NewRemote(server.URL, selenium.NewCapabilities())
+	remote.SetSession("session-id", nil)
+	driver := &chromeDriver{&selenium.LocalDriver{WebDriver: remote}, "ms"}
+
+	identifier, err := driver.AddScriptToEvaluateOnNewDocument("window.loaded = true")
+	require.NoErrorf(t, err, "Script should be added.")
+	require.Equalf(t, "1", identifier, "Identifier of the script should be returned.")
+
+	require.NoErrorf(t, driver.SetExtraHTTPHeaders(map[string]string{"X-Test": "1"}), "Headers should be set.")
+	require.Equalf(t, []map[string]interface{}{
+		{"cmd": "Page.addScriptToEvaluateOnNewDocument", "params": map[string]interface{}{"source": "window.loaded = true"}},
+		{"cmd": "Network.enable", "params": map[string]interface{}{}},
+		{"cmd": "Network.setExtraHTTPHeaders", "params": map[string]interface{}{"headers": map[string]interface{}{"X-Test": "1"}}},
+	}, commands, "Commands should be sent with their params.")
+
+	_, err = driver.ExecuteCDPCommand("Browser.crash", nil)
+	require.Errorf(t, err, "Failed command should be reported.")
+
+	_, err = driver.ExecuteCDPCommand("Browser.getVersion", nil)
+	require.Errorf(t, err, "Result which is not an object should be reported.")
+
+	result, err := driver.ExecuteCDPCommand("Network.clearBrowserCache", nil)
+	require.NoErrorf(t, err, "Command without a result should succeed.")
+	require.Equalf(t, map[string]interface{}{}, result, "Null result should be empty.")
+
+}
+
+func TestExecuteCDPCommandLegacy(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		command := make(map[string]interface{})
+		require.NoErrorf(t, json.NewDecoder(r.Body).Decode(&command), "Command should be JSON.")
+
+		//legacy remote ends report errors with a status in a successful response
+		if command["cmd"] == "Browser.crash" {
+			w.Write([]byte(`{"sessionId": "session-id", "status": 13, "value": {"message": "unknown command"}}`))
+			return
+		}
+
+		w.Write([]byte(`{"sessionId": "session-id", "status": 0, "value": {"identifier": "1"}}`))
+
+	}))
+	defer server.Close()
+
+	remote := selenium.NewRemote(server.URL, selenium.NewCapabilities())
+	remote.SetSession("session-id", nil)
+	remote.SetDialect(selenium.LegacyDialect)
+	driver := &chromeDriver{&selenium.LocalDriver{WebDriver: remote}, "goog"}
+
+	identifier, err := driver.AddScriptToEvaluateOnNewDocument("window.loaded = true")
+	require.NoErrorf(t, err, "Script should be added through a legacy session.")
+	require.Equalf(t, "1", identifier, "Identifier of the script should be returned.")
+
+	_, err = driver.ExecuteCDPCommand("Browser.crash", nil)
+	require.Errorf(t, err, "Legacy error status should be reported.")
+	require.Containsf(t, err.Error(), "unknown command", "Legacy error message should be returned.")
+
+}
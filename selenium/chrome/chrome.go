@@ -1,158 +1,177 @@
 package chrome
 
 import (
+	"context"
 	"errors"
-	"fmt"
-	"os/exec"
-	"strconv"
-	"time"
 
 	"../../selenium"
 	"../by"
 )
 
-type chromeDriver struct {
-	selenium.WebDriver
-	cmd *exec.Cmd
+//ChromiumDriver is the driver of a Chromium based browser, such as Chrome or Edge
+type ChromiumDriver interface {
+	selenium.Driver
+	GetService() *selenium.Service
+	ExecuteCDPCommand(method string, params map[string]interface{}) (map[string]interface{}, error)
+	SetDeviceMetricsOverride(width int, height int, deviceScaleFactor float64, mobile bool) error
+	ClearDeviceMetricsOverride() error
+	SetGeolocationOverride(latitude float64, longitude float64, accuracy float64) error
+	SetTimezoneOverride(timezoneID string) error
+	SetUserAgentOverride(userAgent string) error
+	SetExtraHTTPHeaders(headers map[string]string) error
+	SetBlockedURLs(urls ...string) error
+	EmulateNetworkConditions(conditions *NetworkConditions) error
+	ClearBrowserCache() error
+	ClearBrowserCookies() error
+	AddScriptToEvaluateOnNewDocument(source string) (string, error)
+	RemoveScriptToEvaluateOnNewDocument(identifier string) error
+	CaptureFullPageScreenshot() (selenium.Screenshot, error)
+	DevTools() (*CDPClient, error)
 }
 
-//Driver starts the chromedriver server on the specified port and returns a WebDriver implementation
-func Driver(path string, port int, options *ChromeOptions) (*chromeDriver, error) {
+type chromeDriver struct {
+	*selenium.LocalDriver
+	//vendor prefixes the vendor specific endpoints of the driver, e.g. "goog" for chromedriver or "ms" for msedgedriver
+	vendor string
+}
 
-	cmd := exec.Command(path, "--port="+strconv.Itoa(port))
+//Driver starts the chromedriver server on the specified port, or a free port if 0, and returns a WebDriver implementation.
+//When path is empty chromedriver is looked up from the CHROMEDRIVER environment variable and $PATH.
+//Every command, including the new session, is sent through middlewares.
+func Driver(path string, port int, options *ChromeOptions, middlewares ...selenium.Middleware) (*chromeDriver, error) {
 
-	err := startChromeDriver(cmd)
+	path, err := FindDriver(path)
 	if err != nil {
 		return nil, err
 	}
 
-	url := fmt.Sprintf("http://127.0.0.1:%d", port)
-
-	caps := &Capabilities{selenium.NewCapabilities(), options}
-
-	caps.SetBrowserName("chrome")
-
-	wd := selenium.NewRemote(url, caps)
+	err = checkBrowserVersion(path, options)
 	if err != nil {
 		return nil, err
 	}
 
-	driver := &chromeDriver{wd, cmd}
-
-	for retries := 3; retries > 0; retries-- {
-
-		status, err := driver.GetStatus()
-		if err != nil {
-			if retries == 1 {
-				return nil, err
-			}
-			continue
-		}
+	return DriverWithService(selenium.NewService(path, port), options, middlewares...)
 
-		if status.IsReady() {
-			break
-		}
+}
 
-		time.Sleep(5 * time.Second)
+//DriverWithService starts service, e.g. configured to capture the chromedriver logs, and returns a WebDriver implementation
+func DriverWithService(service *selenium.Service, options *ChromeOptions, middlewares ...selenium.Middleware) (*chromeDriver, error) {
+	return StartChromium(service, "goog", NewChromiumCapabilities("chrome", "goog:chromeOptions", options), middlewares...)
+}
 
-	}
+//StartChromium starts service and a session with caps, returning a driver with the Chromium specific behaviors.
+//It is used by the packages of other Chromium based browsers such as edge, whose vendor prefixes the driver specific endpoints.
+func StartChromium(service *selenium.Service, vendor string, caps selenium.Capabilities, middlewares ...selenium.Middleware) (*chromeDriver, error) {
 
-	_, err = driver.NewSession()
+	driver, err := selenium.StartLocalDriver(service, caps, middlewares...)
 	if err != nil {
 		return nil, err
 	}
 
-	return driver, nil
+	return &chromeDriver{driver, vendor}, nil
 
 }
 
-func (driver *chromeDriver) NewSession() (selenium.SessionInfo, error) {
-
-	//chrome ,
-
-	info, ok := driver.WebDriver.(selenium.WebDriverInfo)
-	if !ok {
-		return nil, errors.New("could not get web driver info")
-	}
+//WithContext returns a copy of the driver, keeping its Chromium specific behaviors, whose commands are aborted when ctx is done
+func (driver *chromeDriver) WithContext(ctx context.Context) selenium.WebDriver {
+	return &chromeDriver{driver.CopyWithContext(ctx), driver.vendor}
+}
 
-	reply, err := selenium.ExecuteWDCommand(
-		selenium.POST,
-		info.GetURL()+"/session",
-		map[string]interface{}{"desiredCapabilities": info.GetDesiredCapabilities()},
-	)
+func (driver *chromeDriver) FindElement(locator *by.Locator) (selenium.WebElement, error) {
 
+	element, err := driver.WebDriver.FindElement(locator)
 	if err != nil {
 		return nil, err
 	}
 
-	if reply.StatusCode != 200 {
-		return nil, errors.New("non 200 status code received")
+	if webElementUpdater, ok := element.(selenium.WebElementUpdater); ok {
+		err := webElementUpdater.SetDriver(driver)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	message, err := reply.GetString("value.message", true)
-	if err == nil {
-		return nil, errors.New(message)
-	}
+	return element, nil
+
+}
+
+func (driver *chromeDriver) FindElements(locator *by.Locator) ([]selenium.WebElement, error) {
 
-	id, err := reply.GetString("sessionId", false)
+	elements, err := driver.WebDriver.FindElements(locator)
 	if err != nil {
 		return nil, err
 	}
+	for _, element := range elements {
+		if webElementUpdater, ok := element.(selenium.WebElementUpdater); ok {
+			err := webElementUpdater.SetDriver(driver)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
 
-	capabilities, err := reply.GetMap("value", false)
+	return elements, nil
+
+}
+
+func (driver *chromeDriver) FindElementFromElement(element selenium.WebElement, locator *by.Locator) (selenium.WebElement, error) {
+
+	element, err := driver.WebDriver.FindElementFromElement(element, locator)
 	if err != nil {
 		return nil, err
 	}
 
-	updater, ok := driver.WebDriver.(selenium.WebDriverUpdater)
-	if !ok {
-		return nil, errors.New("could not get web driver updater")
+	if webElementUpdater, ok := element.(selenium.WebElementUpdater); ok {
+		err := webElementUpdater.SetDriver(driver)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	updater.SetSession(id, capabilities)
-
-	return info.GetSession(), nil
+	return element, nil
 
 }
 
-func (driver *chromeDriver) GetStatus() (*selenium.Status, error) {
-
-	info, ok := driver.WebDriver.(selenium.WebDriverInfo)
-	if !ok {
-		return nil, errors.New("could not get web driver info (chrome.go, 125)")
-	}
-
-	reply, err := selenium.ExecuteWDCommand(
-		selenium.GET,
-		fmt.Sprintf("%s/status", info.GetURL()),
-		nil,
-	)
+func (driver *chromeDriver) FindElementsFromElement(element selenium.WebElement, locator *by.Locator) ([]selenium.WebElement, error) {
 
+	elements, err := driver.WebDriver.FindElementsFromElement(element, locator)
 	if err != nil {
 		return nil, err
 	}
-
-	if reply.StatusCode != 200 {
-		return nil, errors.New("non 200 status code received")
+	for _, element := range elements {
+		if webElementUpdater, ok := element.(selenium.WebElementUpdater); ok {
+			err := webElementUpdater.SetDriver(driver)
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	message, err := reply.GetString("value.message", true)
-	if err == nil {
-		return nil, errors.New(message)
-	}
+	return elements, nil
+
+}
 
-	status, err := reply.GetFloat("status", false)
+func (driver *chromeDriver) GetElementShadowRoot(element selenium.WebElement) (selenium.ShadowRoot, error) {
+
+	root, err := driver.WebDriver.GetElementShadowRoot(element)
 	if err != nil {
 		return nil, err
 	}
 
-	return &selenium.Status{Ready: status == 0, Message: ""}, nil
+	if webElementUpdater, ok := root.(selenium.WebElementUpdater); ok {
+		err := webElementUpdater.SetDriver(driver)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return root, nil
 
 }
 
-func (driver *chromeDriver) FindElement(locator *by.Locator) (selenium.WebElement, error) {
+func (driver *chromeDriver) FindElementFromShadowRoot(root selenium.ShadowRoot, locator *by.Locator) (selenium.WebElement, error) {
 
-	element, err := driver.WebDriver.FindElement(locator)
+	element, err := driver.WebDriver.FindElementFromShadowRoot(root, locator)
 	if err != nil {
 		return nil, err
 	}
@@ -168,9 +187,9 @@ func (driver *chromeDriver) FindElement(locator *by.Locator) (selenium.WebElemen
 
 }
 
-func (driver *chromeDriver) FindElements(locator *by.Locator) ([]selenium.WebElement, error) {
+func (driver *chromeDriver) FindElementsFromShadowRoot(root selenium.ShadowRoot, locator *by.Locator) ([]selenium.WebElement, error) {
 
-	elements, err := driver.WebDriver.FindElements(locator)
+	elements, err := driver.WebDriver.FindElementsFromShadowRoot(root, locator)
 	if err != nil {
 		return nil, err
 	}
@@ -187,40 +206,48 @@ func (driver *chromeDriver) FindElements(locator *by.Locator) ([]selenium.WebEle
 
 }
 
-func (driver *chromeDriver) FindElementFromElement(element selenium.WebElement, locator *by.Locator) (selenium.WebElement, error) {
+func (driver *chromeDriver) ExecuteScript(script string, args ...interface{}) (interface{}, error) {
 
-	element, err := driver.WebDriver.FindElementFromElement(element, locator)
+	value, err := driver.WebDriver.ExecuteScript(script, args...)
 	if err != nil {
 		return nil, err
 	}
 
-	if webElementUpdater, ok := element.(selenium.WebElementUpdater); ok {
-		err := webElementUpdater.SetDriver(driver)
-		if err != nil {
-			return nil, err
-		}
+	err = driver.bindScriptResult(value)
+	if err != nil {
+		return nil, err
 	}
 
-	return element, nil
+	return value, nil
 
 }
 
-func (driver *chromeDriver) FindElementsFromElement(element selenium.WebElement, locator *by.Locator) ([]selenium.WebElement, error) {
+func (driver *chromeDriver) ExecuteAsyncScript(script string, args ...interface{}) (interface{}, error) {
 
-	elements, err := driver.WebDriver.FindElementsFromElement(element, locator)
+	value, err := driver.WebDriver.ExecuteAsyncScript(script, args...)
 	if err != nil {
 		return nil, err
 	}
-	for _, element := range elements {
-		if webElementUpdater, ok := element.(selenium.WebElementUpdater); ok {
-			err := webElementUpdater.SetDriver(driver)
-			if err != nil {
-				return nil, err
-			}
+
+	err = driver.bindScriptResult(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return value, nil
+
+}
+
+func (driver *chromeDriver) bindScriptResult(value interface{}) error {
+
+	for _, reference := range selenium.ScriptResultReferences(value) {
+		err := reference.SetDriver(driver)
+		if err != nil {
+			return err
 		}
 	}
 
-	return elements, nil
+	return nil
 
 }
 
@@ -286,104 +313,6 @@ func (driver *chromeDriver) GetElementProperty(element selenium.WebElement, name
 
 */
 
-func (driver *chromeDriver) ElementSendKeys(element selenium.WebElement, keys string) error {
-
-	elementInfo, ok := element.(selenium.WebElementInfo)
-	if !ok {
-		return errors.New("could not get web element info")
-	}
-
-	driverInfo, ok := driver.WebDriver.(selenium.WebDriverInfo)
-	if !ok {
-		return errors.New("could not get web driver info")
-	}
-
-	chars := make([]string, len(keys))
-	for i, c := range keys {
-		chars[i] = string(c)
-	}
-
-	reply, err := selenium.ExecuteWDCommand(
-		selenium.POST,
-		fmt.Sprintf("%s/session/%s/element/%s/value", driverInfo.GetURL(), driverInfo.GetSession().GetID(), elementInfo.GetValue()),
-		map[string]interface{}{"value": chars},
-	)
-
-	if err != nil {
-		return err
-	}
-
-	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return errors.New(message)
-		}
-		return errors.New("non 200 status code received")
-	}
-
-	return nil
-
-}
-
-func (driver *chromeDriver) Quit() error {
-
-	err := driver.DeleteSession()
-	if err != nil {
-		return err
-	}
-
-	return driver.cmd.Process.Kill()
-
-}
-
-func (driver *chromeDriver) SetTimeouts(timeouts *selenium.Timeouts) error {
-
-	driverInfo, ok := driver.WebDriver.(selenium.WebDriverInfo)
-	if !ok {
-		return errors.New("could not get web driver info")
-	}
-
-	list := []map[string]interface{}{
-
-		map[string]interface{}{
-			"type": "page load",
-			"ms":   timeouts.PageLoad,
-		}, map[string]interface{}{
-			"type": "script",
-			"ms":   timeouts.Script,
-		},
-		map[string]interface{}{
-			"type": "implicit",
-			"ms":   timeouts.Implicit,
-		},
-	}
-
-	for _, timeout := range list {
-
-		reply, err := selenium.ExecuteWDCommand(
-			selenium.POST,
-			fmt.Sprintf("%s/session/%s/timeouts", driverInfo.GetURL(), driverInfo.GetSession().GetID()),
-			timeout,
-		)
-
-		if err != nil {
-			return err
-		}
-
-		if reply.StatusCode != 200 {
-			message, err := reply.GetString("value.message", true)
-			if err == nil {
-				return errors.New(message)
-			}
-			return errors.New("non 200 status code")
-		}
-
-	}
-
-	return nil
-
-}
-
 func (driver *chromeDriver) GetElementRect(element selenium.WebElement) (*selenium.Rect, error) {
 
 	returned, err := driver.WebDriver.ExecuteScript("return arguments[0].getBoundingClientRect()", element)
@@ -420,29 +349,3 @@ func (driver *chromeDriver) GetElementRect(element selenium.WebElement) (*seleni
 	return nil, errors.New("could not parse rect")
 
 }
-
-func startChromeDriver(cmd *exec.Cmd) error {
-
-	/*
-
-		stdout, err := cmd.StdoutPipe()
-		if err != nil {
-			return err
-		}
-
-		stderr, err := cmd.StderrPipe()
-		if err != nil {
-			return err
-		}
-
-	*/
-
-	if err := cmd.Start(); err != nil {
-		return err
-	}
-
-	//go func() { selenium.Log(stdout) }()
-	//go func() { selenium.Log(stderr) }()
-
-	return nil
-}
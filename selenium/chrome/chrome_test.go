@@ -15,7 +15,7 @@ import (
 func TestChrome(t *testing.T) {
 
 	//options := new(ChromeOptions)
-	driver, err := Driver(CHROMEDRIVER_PATH, 4444, nil)
+	driver, err := Driver("", 0, nil)
 	require.NoErrorf(t, err, "Creation of ChromeDriver should not raise any errors.")
 
 	timeouts, err := driver.GetTimeouts()
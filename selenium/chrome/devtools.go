@@ -0,0 +1,353 @@
+package chrome
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"../../selenium"
+	"../errors"
+	"../websocket"
+)
+
+//CDPError is the error of a Chrome DevTools Protocol command
+type CDPError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Data    string `json:"data,omitempty"`
+}
+
+func (e *CDPError) Error() string {
+
+	if e.Data == "" {
+		return fmt.Sprintf("cdp error %d: %s", e.Code, e.Message)
+	}
+
+	return fmt.Sprintf("cdp error %d: %s: %s", e.Code, e.Message, e.Data)
+
+}
+
+//CDPEvent is an event sent by the browser, e.g. "Network.requestWillBeSent".
+//SessionID is the session of the target the event comes from, empty for the browser itself.
+type CDPEvent struct {
+	Method    string
+	Params    map[string]interface{}
+	SessionID string
+}
+
+//TargetInfo describes a target of the browser, such as a page or a worker
+type TargetInfo struct {
+	TargetID string `json:"targetId"`
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	URL      string `json:"url"`
+	Attached bool   `json:"attached"`
+}
+
+type cdpMessage struct {
+	ID        int64                  `json:"id,omitempty"`
+	Method    string                 `json:"method,omitempty"`
+	Params    map[string]interface{} `json:"params,omitempty"`
+	SessionID string                 `json:"sessionId,omitempty"`
+	Result    map[string]interface{} `json:"result,omitempty"`
+	Error     *CDPError              `json:"error,omitempty"`
+}
+
+//CDPClient is a Chrome DevTools Protocol client connected to the browser over a WebSocket.
+//Commands are multiplexed by id and events are delivered on channels, so the client is safe for concurrent use.
+type CDPClient struct {
+	rpc *websocket.RPC
+}
+
+//DialCDP connects to the browser DevTools endpoint webSocketURL, e.g. "ws://127.0.0.1:9222/devtools/browser/<id>"
+func DialCDP(ctx context.Context, webSocketURL string) (*CDPClient, error) {
+
+	conn, err := websocket.Dial(ctx, webSocketURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CDPClient{websocket.NewRPC(conn, routeCDP)}, nil
+
+}
+
+//DevTools connects a CDP client to the browser of the session, discovered from the debuggerAddress capability
+//returned by the driver, e.g. "goog:chromeOptions.debuggerAddress"
+func (driver *chromeDriver) DevTools() (*CDPClient, error) {
+
+	info, ok := driver.WebDriver.(selenium.WebDriverInfo)
+	if !ok {
+		return nil, errors.WebDriverInfoNotImplemented()
+	}
+
+	address := ""
+	for name, value := range info.GetSession().GetCapabilities() {
+		if options, ok := value.(map[string]interface{}); ok && strings.HasPrefix(name, driver.vendor+":") {
+			if debuggerAddress, ok := options["debuggerAddress"].(string); ok {
+				address = debuggerAddress
+			}
+		}
+	}
+
+	if address == "" {
+		return nil, fmt.Errorf("session has no %s debuggerAddress capability", driver.vendor)
+	}
+
+	webSocketURL, err := browserWebSocketURL(info.GetContext(), address)
+	if err != nil {
+		return nil, err
+	}
+
+	return DialCDP(info.GetContext(), webSocketURL)
+
+}
+
+//browserWebSocketURL returns the WebSocket URL of the browser target listed by the /json/version endpoint of address
+func browserWebSocketURL(ctx context.Context, address string) (string, error) {
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+address+"/json/version", nil)
+	if err != nil {
+		return "", err
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return "", err
+	}
+	defer response.Body.Close()
+
+	version := struct {
+		WebSocketDebuggerURL string `json:"webSocketDebuggerUrl"`
+	}{}
+
+	err = json.NewDecoder(response.Body).Decode(&version)
+	if err != nil {
+		return "", err
+	}
+
+	if version.WebSocketDebuggerURL == "" {
+		return "", fmt.Errorf("no webSocketDebuggerUrl listed by %s", address)
+	}
+
+	return version.WebSocketDebuggerURL, nil
+
+}
+
+//Execute sends a command to the browser target and waits for its result, or until ctx is done
+func (client *CDPClient) Execute(ctx context.Context, method string, params map[string]interface{}) (map[string]interface{}, error) {
+	return client.execute(ctx, "", method, params)
+}
+
+func (client *CDPClient) execute(ctx context.Context, sessionID string, method string, params map[string]interface{}) (map[string]interface{}, error) {
+
+	id := client.rpc.NextID()
+
+	data, err := json.Marshal(&cdpMessage{ID: id, Method: method, Params: params, SessionID: sessionID})
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = client.rpc.Call(ctx, id, data)
+	if err != nil {
+		return nil, err
+	}
+
+	reply := new(cdpMessage)
+	err = json.Unmarshal(data, reply)
+	if err != nil {
+		return nil, err
+	}
+
+	if reply.Error != nil {
+		return nil, reply.Error
+	}
+
+	if reply.Result == nil {
+		return make(map[string]interface{}), nil
+	}
+
+	return reply.Result, nil
+
+}
+
+//routeCDP tells the replies, which carry the id of their command, from the events
+func routeCDP(data []byte) (int64, interface{}) {
+
+	message := new(cdpMessage)
+	if json.Unmarshal(data, message) != nil {
+		return 0, nil
+	}
+
+	if message.ID != 0 {
+		return message.ID, nil
+	}
+
+	return 0, &CDPEvent{Method: message.Method, Params: message.Params, SessionID: message.SessionID}
+
+}
+
+//Events returns a channel receiving the events of the browser target named method, or every event if method is empty.
+//Events are queued until received, and the channel is closed by the returned function or when the client is closed.
+//The domain of the events usually needs to be enabled first, e.g. with "Network.enable".
+func (client *CDPClient) Events(method string) (<-chan *CDPEvent, func()) {
+	return client.subscribe("", method)
+}
+
+func (client *CDPClient) subscribe(sessionID string, method string) (<-chan *CDPEvent, func()) {
+
+	subscription := client.rpc.Subscribe(func(event interface{}) bool {
+		cdpEvent := event.(*CDPEvent)
+		return cdpEvent.SessionID == sessionID && (method == "" || cdpEvent.Method == method)
+	})
+
+	events := make(chan *CDPEvent)
+
+	go func() {
+		defer close(events)
+		for event := range subscription.Events() {
+			select {
+			case events <- event.(*CDPEvent):
+			case <-subscription.Done():
+				return
+			}
+		}
+	}()
+
+	return events, func() { client.rpc.Unsubscribe(subscription) }
+
+}
+
+//GetTargets returns the targets of the browser
+func (client *CDPClient) GetTargets(ctx context.Context) ([]*TargetInfo, error) {
+
+	result, err := client.Execute(ctx, "Target.getTargets", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(result["targetInfos"])
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make([]*TargetInfo, 0)
+	err = json.Unmarshal(data, &targets)
+	if err != nil {
+		return nil, err
+	}
+
+	return targets, nil
+
+}
+
+//AttachToTarget attaches a session to the target, whose commands and events are then exchanged over the client connection.
+//Window handles returned by chromedriver are the IDs of their page targets.
+func (client *CDPClient) AttachToTarget(ctx context.Context, targetID string) (*CDPSession, error) {
+
+	result, err := client.Execute(ctx, "Target.attachToTarget", map[string]interface{}{"targetId": targetID, "flatten": true})
+	if err != nil {
+		return nil, err
+	}
+
+	sessionID, ok := result["sessionId"].(string)
+	if !ok {
+		return nil, fmt.Errorf("Target.attachToTarget returned no sessionId: %v", result)
+	}
+
+	return &CDPSession{client: client, id: sessionID, targetID: targetID}, nil
+
+}
+
+//AutoAttach makes the browser attach a session to each of its existing and new targets, such as pages, popups and workers,
+//and calls attached with every session. New targets wait for attached to return before running, so that it can enable
+//domains without missing their first events. ctx bounds the commands sent while attaching.
+//The returned function stops calling attached, and returns once the call in progress, if any, is done.
+func (client *CDPClient) AutoAttach(ctx context.Context, attached func(session *CDPSession, target *TargetInfo)) (func(), error) {
+
+	events, unsubscribe := client.Events("Target.attachedToTarget")
+
+	params := map[string]interface{}{"autoAttach": true, "waitForDebuggerOnStart": true, "flatten": true}
+
+	_, err := client.Execute(ctx, "Target.setAutoAttach", params)
+	if err != nil {
+		unsubscribe()
+		return nil, err
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+
+		defer close(done)
+
+		for event := range events {
+
+			sessionID, _ := event.Params["sessionId"].(string)
+
+			target := new(TargetInfo)
+			if data, err := json.Marshal(event.Params["targetInfo"]); err == nil {
+				json.Unmarshal(data, target)
+			}
+
+			session := &CDPSession{client: client, id: sessionID, targetID: target.TargetID}
+			attached(session, target)
+
+			//the target was paused by waitForDebuggerOnStart, an error meaning it was already running
+			session.Execute(ctx, "Runtime.runIfWaitingForDebugger", nil)
+
+		}
+
+	}()
+
+	stop := func() {
+		unsubscribe()
+		<-done
+	}
+
+	return stop, nil
+
+}
+
+//Close closes the connection, failing the pending commands and closing the event channels
+func (client *CDPClient) Close() error {
+	return client.rpc.Close()
+}
+
+//CDPSession is a session attached to a target of the browser, such as a page
+type CDPSession struct {
+	client   *CDPClient
+	id       string
+	targetID string
+}
+
+//GetID returns the session ID
+func (session *CDPSession) GetID() string {
+	return session.id
+}
+
+//GetTargetID returns the ID of the target the session is attached to
+func (session *CDPSession) GetTargetID() string {
+	return session.targetID
+}
+
+//Execute sends a command to the target and waits for its result, or until ctx is done
+func (session *CDPSession) Execute(ctx context.Context, method string, params map[string]interface{}) (map[string]interface{}, error) {
+	return session.client.execute(ctx, session.id, method, params)
+}
+
+//Events returns a channel receiving the events of the target named method, or every event if method is empty.
+//Events are queued until received, and the channel is closed by the returned function or when the client is closed.
+func (session *CDPSession) Events(method string) (<-chan *CDPEvent, func()) {
+	return session.client.subscribe(session.id, method)
+}
+
+//Detach detaches the session from its target
+func (session *CDPSession) Detach(ctx context.Context) error {
+
+	_, err := session.client.Execute(ctx, "Target.detachFromTarget", map[string]interface{}{"sessionId": session.id})
+
+	return err
+
+}
@@ -0,0 +1,98 @@
+package chrome
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"../../selenium"
+	"../seleniumtest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDevTools(t *testing.T) {
+
+	server := seleniumtest.NewCDPServer()
+	defer server.Close()
+
+	remote := selenium.NewRemote(server.URL, selenium.NewCapabilities())
+	remote.SetSession("session-id", map[string]interface{}{
+		"goog:chromeOptions": map[string]interface{}{"debuggerAddress": server.Address()},
+	})
+	driver := &chromeDriver{&selenium.LocalDriver{WebDriver: remote}, "goog"}
+
+	client, err := driver.DevTools()
+	require.NoErrorf(t, err, "Client should connect to the discovered debugger address.")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := client.Execute(ctx, "Browser.getVersion", nil)
+	require.NoErrorf(t, err, "Browser command should succeed.")
+	require.Equalf(t, "Chrome/120.0.6099.109", result["product"], "Result should be decoded.")
+
+	_, err = client.Execute(ctx, "Browser.crash", nil)
+	cdpErr := new(CDPError)
+	require.ErrorAsf(t, err, &cdpErr, "Protocol error should be returned.")
+	require.Equalf(t, -32601, cdpErr.Code, "Protocol error code should be decoded.")
+
+	session, err := client.AttachToTarget(ctx, "window-handle")
+	require.NoErrorf(t, err, "Session should be attached.")
+	require.Equalf(t, "page-session", session.GetID(), "Session ID should be returned.")
+
+	events, unsubscribe := session.Events("Page.loadEventFired")
+	defer unsubscribe()
+
+	_, err = session.Execute(ctx, "Page.enable", nil)
+	require.NoErrorf(t, err, "Session command should succeed.")
+
+	select {
+	case event := <-events:
+		require.Equalf(t, 1.5, event.Params["timestamp"], "Only the events of the session should be received.")
+	case <-ctx.Done():
+		t.Fatal("Event should be received.")
+	}
+
+	require.NoErrorf(t, client.Close(), "Client should close.")
+
+	_, ok := <-events
+	require.Falsef(t, ok, "Event channels should be closed with the client.")
+
+	_, err = client.Execute(ctx, "Browser.getVersion", nil)
+	require.Errorf(t, err, "Commands should fail once the client is closed.")
+
+}
+
+func TestAutoAttach(t *testing.T) {
+
+	server := seleniumtest.NewCDPServer()
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client, err := DialCDP(ctx, server.WebSocketURL())
+	require.NoErrorf(t, err, "Client should connect.")
+	defer client.Close()
+
+	sessions := make(chan *CDPSession, 2)
+
+	stop, err := client.AutoAttach(ctx, func(session *CDPSession, target *TargetInfo) {
+		if target.Type == "page" {
+			sessions <- session
+		}
+	})
+	require.NoErrorf(t, err, "Auto attach should be enabled.")
+
+	for _, expected := range []string{"page-session", "popup-session"} {
+		select {
+		case session := <-sessions:
+			require.Equalf(t, expected, session.GetID(), "Every page target should be attached.")
+		case <-ctx.Done():
+			t.Fatal("Target should be attached.")
+		}
+	}
+
+	stop()
+
+}
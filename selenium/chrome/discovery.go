@@ -0,0 +1,78 @@
+package chrome
+
+import "../../selenium"
+
+//DriverEnv is the environment variable naming the chromedriver binary
+const DriverEnv = "CHROMEDRIVER"
+
+//browserNames are the Chrome binaries looked up on $PATH
+var browserNames = []string{"google-chrome", "google-chrome-stable", "chromium", "chromium-browser", "chrome"}
+
+//FindDriver returns the path of chromedriver from the explicit path, the CHROMEDRIVER environment variable or $PATH
+func FindDriver(path string) (string, error) {
+	return selenium.FindBinary(path, DriverEnv, "chromedriver")
+}
+
+//FindBrowser returns the path of Chrome from the explicit binary or $PATH
+func FindBrowser(binary string) (string, error) {
+	return selenium.FindBinary(binary, "", browserNames...)
+}
+
+//CheckVersions returns a selenium.VersionMismatchError if chromedriver at driverPath does not support the major version of Chrome at browserPath
+func CheckVersions(driverPath string, browserPath string) error {
+	return CheckChromiumVersions("chromedriver", driverPath, "Chrome", browserPath)
+}
+
+//CheckChromiumVersions returns a selenium.VersionMismatchError if the driver at driverPath does not support the major version of the
+//Chromium based browser at browserPath. Chromium drivers only support the browser version they were released with.
+func CheckChromiumVersions(driver string, driverPath string, browser string, browserPath string) error {
+
+	driverVersion, err := selenium.BinaryVersion(driverPath)
+	if err != nil {
+		return err
+	}
+
+	browserVersion, err := selenium.BinaryVersion(browserPath)
+	if err != nil {
+		return err
+	}
+
+	return selenium.CheckMajorVersions(driver, driverVersion, browser, browserVersion)
+
+}
+
+//checkBrowserVersion fails fast when the Chrome used by options is not supported by chromedriver.
+//The check is skipped when Chrome cannot be found or does not report its version, e.g. on Windows.
+func checkBrowserVersion(driverPath string, options *ChromeOptions) error {
+
+	binary := ""
+	if options != nil {
+		binary = options.Binary
+	}
+
+	browserPath, err := FindBrowser(binary)
+	if err != nil {
+		return nil
+	}
+
+	return CheckChromiumBrowserVersion("chromedriver", driverPath, "Chrome", browserPath)
+
+}
+
+//CheckChromiumBrowserVersion is CheckChromiumVersions skipping the check when the browser does not report its version,
+//running each binary only once
+func CheckChromiumBrowserVersion(driver string, driverPath string, browser string, browserPath string) error {
+
+	browserVersion, err := selenium.BinaryVersion(browserPath)
+	if err != nil {
+		return nil
+	}
+
+	driverVersion, err := selenium.BinaryVersion(driverPath)
+	if err != nil {
+		return err
+	}
+
+	return selenium.CheckMajorVersions(driver, driverVersion, browser, browserVersion)
+
+}
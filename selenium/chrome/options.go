@@ -1,21 +1,29 @@
 package chrome
 
+import (
+	"fmt"
+
+	"../../selenium"
+)
+
 //TODO: add comments to exported
 
 //ChromeOptions is an implementatin of the optional chromeOptions key included in desired capabilities
 type ChromeOptions struct {
-	Args             []string               `json:",omitempty"`
-	Binary           string                 `json:",omitempty"`
-	Extensions       []string               `json:",omitempty"`
-	LocalState       map[string]interface{} `json:",omitempty"`
-	Prefs            map[string]interface{} `json:",omitempty"`
-	Detach           bool                   `json:",omitempty"`
-	DebuggerAddress  string                 `json:",omitempty"`
-	ExcludeSwitches  []string               `json:",omitempty"`
-	MinidumpPath     string                 `json:",omitempty"`
-	MobileEmulation  map[string]interface{} `json:",omitempty"`
-	PerfLoggingPrefs *PerfLoggingPrefs      `json:",omitempty"`
-	WindowTypes      []string               `json:",omitempty"`
+	Args             []string               `json:"args,omitempty"`
+	Binary           string                 `json:"binary,omitempty"`
+	Extensions       []string               `json:"extensions,omitempty"`
+	LocalState       map[string]interface{} `json:"localState,omitempty"`
+	Prefs            map[string]interface{} `json:"prefs,omitempty"`
+	Detach           bool                   `json:"detach,omitempty"`
+	DebuggerAddress  string                 `json:"debuggerAddress,omitempty"`
+	ExcludeSwitches  []string               `json:"excludeSwitches,omitempty"`
+	MinidumpPath     string                 `json:"minidumpPath,omitempty"`
+	MobileEmulation  map[string]interface{} `json:"mobileEmulation,omitempty"`
+	PerfLoggingPrefs *PerfLoggingPrefs      `json:"perfLoggingPrefs,omitempty"`
+	WindowTypes      []string               `json:"windowTypes,omitempty"`
+
+	selenium.BrowserOptions
 }
 
 func (options *ChromeOptions) AddArgs(args ...string) {
@@ -31,10 +39,16 @@ func (options *ChromeOptions) AddExtensions(extensions ...string) {
 }
 
 func (options *ChromeOptions) AddLocalState(name string, value interface{}) {
+	if options.LocalState == nil {
+		options.LocalState = make(map[string]interface{})
+	}
 	options.LocalState[name] = value
 }
 
 func (options *ChromeOptions) AddPref(name string, value interface{}) {
+	if options.Prefs == nil {
+		options.Prefs = make(map[string]interface{})
+	}
 	options.Prefs[name] = value
 }
 
@@ -66,10 +80,24 @@ func (options *ChromeOptions) AddWindowTypes(types ...string) {
 	options.WindowTypes = append(options.WindowTypes, types...)
 }
 
+//SetHeadless runs Chrome without a visible window
+func (options *ChromeOptions) SetHeadless(headless bool) {
+	options.Args = selenium.RemoveArg(options.Args, "--headless", 0)
+	if headless {
+		options.AddArgs("--headless=new")
+	}
+}
+
+//SetWindowSize sets the initial size of the browser window
+func (options *ChromeOptions) SetWindowSize(width int, height int) {
+	options.Args = selenium.RemoveArg(options.Args, "--window-size", 0)
+	options.AddArgs(fmt.Sprintf("--window-size=%d,%d", width, height))
+}
+
 //PerfLoggingPrefs specifies performance logging preferences
 type PerfLoggingPrefs struct {
-	EnableNetwork                bool   `json:",omitempty"`
-	EnablePage                   bool   `json:",omitempty"`
-	TraceCategories              string `json:",omitempty"`
-	BufferUsageReportingInterval int    `json:",omitempty"`
+	EnableNetwork                bool   `json:"enableNetwork,omitempty"`
+	EnablePage                   bool   `json:"enablePage,omitempty"`
+	TraceCategories              string `json:"traceCategories,omitempty"`
+	BufferUsageReportingInterval int    `json:"bufferUsageReportingInterval,omitempty"`
 }
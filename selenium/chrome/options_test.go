@@ -0,0 +1,32 @@
+package chrome
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapabilitiesJSON(t *testing.T) {
+
+	options := new(ChromeOptions)
+	options.SetHeadless(true)
+	options.SetWindowSize(800, 600)
+	options.SetWindowSize(1024, 768)
+	options.AddPref("download.default_directory", "/tmp")
+	options.SetAcceptInsecureCerts(true)
+	options.SetWebSocketURL(true)
+
+	data, err := json.Marshal(NewChromiumCapabilities("chrome", "goog:chromeOptions", options))
+	require.NoErrorf(t, err, "Capabilities should be encoded.")
+	require.JSONEqf(t, `{
+		"browserName": "chrome",
+		"acceptInsecureCerts": true,
+		"webSocketUrl": true,
+		"goog:chromeOptions": {
+			"args": ["--headless=new", "--window-size=1024,768"],
+			"prefs": {"download.default_directory": "/tmp"}
+		}
+	}`, string(data), "Capabilities should be a single W3C object with the chrome options.")
+
+}
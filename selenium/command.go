@@ -1,11 +1,7 @@
 package selenium
 
 import (
-	"bytes"
-	"encoding/json"
-	"errors"
-	"io/ioutil"
-	"net/http"
+	"context"
 )
 
 type Method string
@@ -16,55 +12,18 @@ const (
 	DELETE Method = "DELETE"
 )
 
+//ExecuteWDCommand sends a WebDriver command to the absolute endpoint URL without a deadline
 func ExecuteWDCommand(method Method, endpoint string, data interface{}) (*Reply, error) {
+	return ExecuteWDCommandContext(context.Background(), method, endpoint, data)
+}
 
-	var resp *http.Response
-	var err error
-
-	if method == POST {
-
-		if data != nil {
-			jsonData, err := json.Marshal(data)
-			if err != nil {
-				return nil, err
-			}
-			resp, err = http.Post(endpoint, "application/json", bytes.NewBuffer(jsonData))
-		} else {
-			resp, err = http.Post(endpoint, "", nil)
-		}
-
-	} else if method == GET {
-		resp, err = http.Get(endpoint)
-	} else if method == DELETE {
-		client := &http.Client{}
-		req, err := http.NewRequest("DELETE", endpoint, nil)
-		if err != nil {
-			return nil, err
-		}
-
-		resp, err = client.Do(req)
-
-	} else {
-		return nil, errors.New("unsupported http method: " + string(method))
-	}
-
-	if err != nil {
-		return nil, err
-	}
-
-	defer resp.Body.Close()
-
-	body, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	replyData := make(map[string]interface{}, 0)
-	err = json.Unmarshal(body, &replyData)
-	if err != nil {
-		return nil, err
-	}
-
-	return &Reply{StatusCode: resp.StatusCode, Data: replyData}, nil
+//ExecuteWDCommandContext sends a WebDriver command to the absolute endpoint URL, aborting the request when ctx is done
+func ExecuteWDCommandContext(ctx context.Context, method Method, endpoint string, data interface{}) (*Reply, error) {
+	return NewHTTPCommandExecutor("").Execute(ctx, method, endpoint, data)
+}
 
+//ExecuteSessionCommand sends a command of the session of driver, such as a vendor command, in the context of driver.
+//Like the WebDriver commands, it is translated to the dialect negotiated for the session.
+func ExecuteSessionCommand(driver WebDriverInfo, method Method, endpoint string, data interface{}) (*Reply, error) {
+	return executeDialect(driver.GetContext(), driver.GetExecutor(), driver.GetSession().GetDialect(), method, endpoint, data)
 }
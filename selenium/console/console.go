@@ -0,0 +1,523 @@
+//Package console collects the console messages and uncaught exceptions of the pages of a session
+package console
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"../../selenium"
+	"../bidi"
+	"../chrome"
+)
+
+//Level is the severity of an entry
+type Level string
+
+//Levels of the entries, LevelError being severe
+const (
+	LevelDebug   Level = "debug"
+	LevelInfo    Level = "info"
+	LevelWarning Level = "warn"
+	LevelError   Level = "error"
+)
+
+//Sources of the entries
+const (
+	//SourceConsole is a call of a console method, e.g. console.error
+	SourceConsole = "console"
+	//SourceJavaScript is an uncaught exception or unhandled promise rejection
+	SourceJavaScript = "javascript"
+)
+
+//Modes of collection, from the most to the least complete
+const (
+	ModeBiDi   = "bidi"
+	ModeCDP    = "cdp"
+	ModeScript = "script"
+)
+
+//maxEntries bounds the buffer, the oldest entries being dropped first
+const maxEntries = 10000
+
+//Entry is a console message or an uncaught exception
+type Entry struct {
+	Level     Level
+	Source    string
+	Text      string
+	Timestamp time.Time
+}
+
+func (entry *Entry) String() string {
+	return fmt.Sprintf("[%s] %s: %s", entry.Level, entry.Source, entry.Text)
+}
+
+//IsSevere reports whether the entry is an error
+func (entry *Entry) IsSevere() bool {
+	return entry.Level == LevelError
+}
+
+//SevereErrorsError is returned by AssertNoSevereErrors with the severe entries collected
+type SevereErrorsError struct {
+	Entries []*Entry
+}
+
+func (e *SevereErrorsError) Error() string {
+
+	lines := make([]string, len(e.Entries))
+	for i, entry := range e.Entries {
+		lines[i] = entry.String()
+	}
+
+	return fmt.Sprintf("%d severe browser errors:\n%s", len(e.Entries), strings.Join(lines, "\n"))
+
+}
+
+//devTools is implemented by the drivers of Chromium based browsers
+type devTools interface {
+	DevTools() (*chrome.CDPClient, error)
+}
+
+//Capture collects the entries of a session into a buffer
+type Capture struct {
+	driver selenium.WebDriver
+	mode   string
+
+	mutex   sync.Mutex
+	entries []*Entry
+
+	//scripted is set while the console wrapper is read, in the script mode; it is guarded by mutex, as is stop
+	scripted bool
+	//stop releases the connection of the BiDi and CDP modes
+	stop    func() error
+	stopped chan struct{}
+}
+
+//Start starts collecting the entries of the session of driver, falling back to the next mode when one is not available.
+//It listens to log.entryAdded when the session was started
+//with the webSocketUrl capability, to the Runtime domain of every page with the DevTools of Chromium based browsers,
+//and otherwise injects a console wrapper with ExecuteScript. The wrapper, which works with any WebDriver, only sees the
+//entries of the current page after its first read, and is installed again by every read after a navigation.
+func Start(driver selenium.WebDriver) (*Capture, error) {
+
+	ctx := context.Background()
+	if info, ok := driver.(selenium.WebDriverInfo); ok {
+		ctx = info.GetContext()
+	}
+
+	capture := &Capture{driver: driver, stopped: make(chan struct{})}
+
+	if client, err := bidi.Connect(driver); err == nil {
+		if capture.startBiDi(ctx, client) == nil {
+			return capture, nil
+		}
+	}
+
+	if chromium, ok := driver.(devTools); ok {
+		if client, err := chromium.DevTools(); err == nil {
+			if capture.startCDP(ctx, client) == nil {
+				return capture, nil
+			}
+		}
+	}
+
+	capture.mode = ModeScript
+	capture.mutex.Lock()
+	capture.scripted = true
+	capture.mutex.Unlock()
+	close(capture.stopped)
+
+	_, err := capture.collectScript()
+	if err != nil {
+		return nil, err
+	}
+
+	return capture, nil
+
+}
+
+func (capture *Capture) startBiDi(ctx context.Context, client *bidi.Client) error {
+
+	capture.mode = ModeBiDi
+
+	events, unsubscribe := client.Events(bidi.LogEntryAdded)
+
+	err := client.Subscribe(ctx, []string{bidi.LogEntryAdded})
+	if err != nil {
+		unsubscribe()
+		client.Close()
+		return err
+	}
+
+	go func() {
+		defer close(capture.stopped)
+		for event := range events {
+			entry := new(bidi.LogEntry)
+			if event.Decode(entry) == nil {
+				capture.add(bidiEntry(entry))
+			}
+		}
+	}()
+
+	capture.stop = func() error {
+		unsubscribe()
+		return client.Close()
+	}
+
+	return nil
+
+}
+
+func bidiEntry(entry *bidi.LogEntry) *Entry {
+
+	source := SourceConsole
+	if entry.Type == "javascript" {
+		source = SourceJavaScript
+	}
+
+	return &Entry{
+		Level:     Level(entry.Level),
+		Source:    source,
+		Text:      entry.Text,
+		Timestamp: time.UnixMilli(entry.Timestamp),
+	}
+
+}
+
+func (capture *Capture) startCDP(ctx context.Context, client *chrome.CDPClient) error {
+
+	capture.mode = ModeCDP
+
+	var (
+		forwarders sync.WaitGroup
+		mutex      sync.Mutex
+		//unsubscribes release the events of the attached sessions, and detached lists the sessions
+		//whose target detached before their attachment was handled
+		unsubscribes = make(map[string]func())
+		detached     = make(map[string]bool)
+	)
+
+	//the subscription of a session is released when its target, e.g. a closed popup, detaches
+	detachedEvents, stopDetached := client.Events("Target.detachedFromTarget")
+	detaching := make(chan struct{})
+
+	go func() {
+		defer close(detaching)
+		for event := range detachedEvents {
+			sessionID, _ := event.Params["sessionId"].(string)
+			mutex.Lock()
+			unsubscribe, ok := unsubscribes[sessionID]
+			delete(unsubscribes, sessionID)
+			if !ok {
+				detached[sessionID] = true
+			}
+			mutex.Unlock()
+			if ok {
+				unsubscribe()
+			}
+		}
+	}()
+
+	//every page, including the popups and tabs opened later, is attached with its own session
+	stopAttaching, err := client.AutoAttach(ctx, func(session *chrome.CDPSession, target *chrome.TargetInfo) {
+
+		events, unsubscribe := session.Events("")
+
+		mutex.Lock()
+		if detached[session.GetID()] {
+			delete(detached, session.GetID())
+			mutex.Unlock()
+			unsubscribe()
+			return
+		}
+		unsubscribes[session.GetID()] = unsubscribe
+		mutex.Unlock()
+
+		forwarders.Add(1)
+		go func() {
+			defer forwarders.Done()
+			for event := range events {
+				if entry := cdpEntry(event); entry != nil {
+					capture.add(entry)
+				}
+			}
+		}()
+
+		//Runtime.enable also reports the console messages logged before
+		session.Execute(ctx, "Runtime.enable", nil)
+
+	})
+	if err != nil {
+		stopDetached()
+		<-detaching
+		client.Close()
+		return err
+	}
+
+	capture.stop = func() error {
+
+		stopAttaching()
+		stopDetached()
+		<-detaching
+
+		mutex.Lock()
+		for sessionID, unsubscribe := range unsubscribes {
+			unsubscribe()
+			delete(unsubscribes, sessionID)
+		}
+		mutex.Unlock()
+
+		err := client.Close()
+		forwarders.Wait()
+		close(capture.stopped)
+
+		return err
+
+	}
+
+	return nil
+
+}
+
+func cdpEntry(event *chrome.CDPEvent) *Entry {
+
+	timestamp, _ := event.Params["timestamp"].(float64)
+
+	switch event.Method {
+
+	case "Runtime.consoleAPICalled":
+
+		kind, _ := event.Params["type"].(string)
+		level := LevelInfo
+		switch kind {
+		case "debug":
+			level = LevelDebug
+		case "warning":
+			level = LevelWarning
+		case "error", "assert":
+			level = LevelError
+		}
+
+		args, _ := event.Params["args"].([]interface{})
+		texts := make([]string, 0, len(args))
+		for _, arg := range args {
+			texts = append(texts, remoteObjectText(arg))
+		}
+
+		return &Entry{Level: level, Source: SourceConsole, Text: strings.Join(texts, " "), Timestamp: time.UnixMilli(int64(timestamp))}
+
+	case "Runtime.exceptionThrown":
+
+		details, _ := event.Params["exceptionDetails"].(map[string]interface{})
+		text, _ := details["text"].(string)
+		if exception, ok := details["exception"].(map[string]interface{}); ok {
+			if description, ok := exception["description"].(string); ok {
+				text = description
+			}
+		}
+
+		return &Entry{Level: LevelError, Source: SourceJavaScript, Text: text, Timestamp: time.UnixMilli(int64(timestamp))}
+
+	}
+
+	return nil
+
+}
+
+//remoteObjectText formats a Runtime.RemoteObject argument of a console call
+func remoteObjectText(arg interface{}) string {
+
+	object, _ := arg.(map[string]interface{})
+
+	if value, ok := object["value"]; ok {
+		return fmt.Sprint(value)
+	}
+
+	if description, ok := object["description"].(string); ok {
+		return description
+	}
+
+	if kind, ok := object["type"].(string); ok {
+		return kind
+	}
+
+	return ""
+
+}
+
+//consoleScript installs the console wrapper in the current page, unless already installed, and drains its buffer
+const consoleScript = `
+var w = window;
+if (!w.__seleniumConsole) {
+	var buffer = w.__seleniumConsole = [];
+	var push = function (level, source, text) {
+		buffer.push({level: level, source: source, text: text, timestamp: Date.now()});
+		if (buffer.length > 10000) { buffer.shift(); }
+	};
+	['debug', 'log', 'info', 'warn', 'error'].forEach(function (method) {
+		var original = console[method];
+		console[method] = function () {
+			push(method === 'log' ? 'info' : method, 'console', Array.prototype.map.call(arguments, String).join(' '));
+			return original.apply(console, arguments);
+		};
+	});
+	w.addEventListener('error', function (event) {
+		push('error', 'javascript', event.error && event.error.stack || event.message);
+	});
+	w.addEventListener('unhandledrejection', function (event) {
+		var reason = event.reason;
+		push('error', 'javascript', 'Uncaught (in promise) ' + (reason && reason.stack || String(reason)));
+	});
+}
+return w.__seleniumConsole.splice(0);
+`
+
+//collectScript moves the entries buffered by the console wrapper of the current page to the capture
+func (capture *Capture) collectScript() ([]*Entry, error) {
+
+	value, err := capture.driver.ExecuteScript(consoleScript)
+	if err != nil {
+		return nil, err
+	}
+
+	items, _ := value.([]interface{})
+	entries := make([]*Entry, 0, len(items))
+
+	for _, item := range items {
+
+		fields, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		level, _ := fields["level"].(string)
+		source, _ := fields["source"].(string)
+		text, _ := fields["text"].(string)
+		timestamp, _ := fields["timestamp"].(float64)
+
+		entry := &Entry{Level: Level(level), Source: source, Text: text, Timestamp: time.UnixMilli(int64(timestamp))}
+		capture.add(entry)
+		entries = append(entries, entry)
+
+	}
+
+	return entries, nil
+
+}
+
+func (capture *Capture) add(entry *Entry) {
+
+	capture.mutex.Lock()
+	defer capture.mutex.Unlock()
+
+	capture.entries = append(capture.entries, entry)
+	if len(capture.entries) > maxEntries {
+		capture.entries = capture.entries[len(capture.entries)-maxEntries:]
+	}
+
+}
+
+//Mode returns how the entries are collected: ModeBiDi, ModeCDP or ModeScript
+func (capture *Capture) Mode() string {
+	return capture.mode
+}
+
+//Entries returns the entries collected so far, oldest first
+func (capture *Capture) Entries() ([]*Entry, error) {
+
+	if capture.isScripted() {
+		if _, err := capture.collectScript(); err != nil {
+			return nil, err
+		}
+	}
+
+	capture.mutex.Lock()
+	defer capture.mutex.Unlock()
+
+	return append(make([]*Entry, 0, len(capture.entries)), capture.entries...), nil
+
+}
+
+//SevereErrors returns the error entries collected so far
+func (capture *Capture) SevereErrors() ([]*Entry, error) {
+
+	entries, err := capture.Entries()
+	if err != nil {
+		return nil, err
+	}
+
+	severe := make([]*Entry, 0)
+	for _, entry := range entries {
+		if entry.IsSevere() {
+			severe = append(severe, entry)
+		}
+	}
+
+	return severe, nil
+
+}
+
+//AssertNoSevereErrors returns a *SevereErrorsError listing the error entries collected so far, if any
+func (capture *Capture) AssertNoSevereErrors() error {
+
+	severe, err := capture.SevereErrors()
+	if err != nil {
+		return err
+	}
+
+	if len(severe) > 0 {
+		return &SevereErrorsError{Entries: severe}
+	}
+
+	return nil
+
+}
+
+//Clear discards the entries collected so far
+func (capture *Capture) Clear() error {
+
+	if capture.isScripted() {
+		if _, err := capture.collectScript(); err != nil {
+			return err
+		}
+	}
+
+	capture.mutex.Lock()
+	defer capture.mutex.Unlock()
+
+	capture.entries = nil
+
+	return nil
+
+}
+
+//Stop stops collecting entries. The entries collected remain readable.
+func (capture *Capture) Stop() error {
+
+	capture.mutex.Lock()
+	capture.scripted = false
+	stop := capture.stop
+	capture.stop = nil
+	capture.mutex.Unlock()
+
+	if stop == nil {
+		return nil
+	}
+
+	err := stop()
+	<-capture.stopped
+
+	return err
+
+}
+
+func (capture *Capture) isScripted() bool {
+
+	capture.mutex.Lock()
+	defer capture.mutex.Unlock()
+
+	return capture.scripted
+
+}
@@ -0,0 +1,242 @@
+package console
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"../../selenium"
+	"../chrome"
+	"../seleniumtest"
+	"../websocket"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaptureScript(t *testing.T) {
+
+	server := seleniumtest.NewServer()
+	defer server.Close()
+
+	//the fake page logs a warning and throws once the wrapper is installed
+	calls := 0
+	server.HandleScript(func(session *seleniumtest.Session, script string, args []interface{}) (interface{}, error) {
+		require.Containsf(t, script, "__seleniumConsole", "Console wrapper should be injected.")
+		calls++
+		if calls == 2 {
+			return []interface{}{
+				map[string]interface{}{"level": "warn", "source": "console", "text": "deprecated API", "timestamp": 1700000000000.0},
+				map[string]interface{}{"level": "error", "source": "javascript", "text": "TypeError: x is undefined", "timestamp": 1700000000001.0},
+			}, nil
+		}
+		return []interface{}{}, nil
+	})
+
+	wd := selenium.NewRemote(server.URL, selenium.NewCapabilities())
+	_, err := wd.NewSession()
+	require.NoErrorf(t, err, "Session should be created by the fake remote end.")
+
+	capture, err := Start(wd)
+	require.NoErrorf(t, err, "Capture should start.")
+	require.Equalf(t, ModeScript, capture.Mode(), "Remote end without BiDi or CDP should fall back to the script.")
+
+	entries, err := capture.Entries()
+	require.NoErrorf(t, err, "Entries should be read.")
+	require.Equalf(t, 2, len(entries), "Buffered entries should be collected.")
+	require.Equalf(t, LevelWarning, entries[0].Level, "Level should be decoded.")
+	require.Equalf(t, time.UnixMilli(1700000000000), entries[0].Timestamp, "Timestamp should be decoded.")
+
+	err = capture.AssertNoSevereErrors()
+	severe := new(SevereErrorsError)
+	require.ErrorAsf(t, err, &severe, "Uncaught exception should be reported.")
+	require.Equalf(t, "TypeError: x is undefined", severe.Entries[0].Text, "Only severe entries should be reported.")
+
+	require.NoErrorf(t, capture.Clear(), "Entries should be cleared.")
+	require.NoErrorf(t, capture.AssertNoSevereErrors(), "Cleared entries should not be reported.")
+	require.NoErrorf(t, capture.Stop(), "Capture should stop.")
+
+}
+
+//newBiDiServer returns a fake BiDi remote end logging an error after every command
+func newBiDiServer() *httptest.Server {
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		ws, err := websocket.Upgrade(w, r)
+		if err != nil {
+			return
+		}
+		defer ws.Close()
+
+		for {
+
+			data, err := ws.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			received := struct {
+				ID int64 `json:"id"`
+			}{}
+			json.Unmarshal(data, &received)
+
+			reply, _ := json.Marshal(map[string]interface{}{"type": "success", "id": received.ID, "result": map[string]interface{}{}})
+			ws.WriteMessage(reply)
+
+			ws.WriteMessage([]byte(`{"type": "event", "method": "log.entryAdded", "params": {
+				"type": "console", "level": "error", "text": "failed to load resource",
+				"source": {"realm": "1"}, "timestamp": 1700000000000}}`))
+
+		}
+
+	}))
+
+}
+
+func TestCaptureBiDi(t *testing.T) {
+
+	server := newBiDiServer()
+	defer server.Close()
+
+	remote := selenium.NewRemote(server.URL, selenium.NewCapabilities())
+	remote.SetSession("session-id", map[string]interface{}{"webSocketUrl": "ws" + strings.TrimPrefix(server.URL, "http")})
+
+	capture, err := Start(remote)
+	require.NoErrorf(t, err, "Capture should start.")
+	require.Equalf(t, ModeBiDi, capture.Mode(), "Session with a webSocketUrl should use BiDi.")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		entries, err := capture.Entries()
+		require.NoErrorf(t, err, "Entries should be read.")
+		if len(entries) > 0 {
+			require.Equalf(t, "failed to load resource", entries[0].Text, "Log entry should be collected.")
+			break
+		}
+		require.Truef(t, time.Now().Before(deadline), "Log entry should be received.")
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	require.NoErrorf(t, capture.Stop(), "Capture should stop.")
+
+}
+
+//cdpDriver is a Chromium driver whose DevTools are served by a fake endpoint
+type cdpDriver struct {
+	selenium.WebDriver
+	webSocketURL string
+}
+
+func (driver *cdpDriver) DevTools() (*chrome.CDPClient, error) {
+	return chrome.DialCDP(context.Background(), driver.webSocketURL)
+}
+
+func TestCaptureCDP(t *testing.T) {
+
+	server := seleniumtest.NewCDPServer()
+	defer server.Close()
+
+	//the page logs to the console and throws, the popup only throws
+	server.Handle("Runtime.enable", func(command *seleniumtest.CDPMessage, emit func(event *seleniumtest.CDPMessage)) map[string]interface{} {
+
+		event := func(method string, params map[string]interface{}) {
+			params["timestamp"] = 1700000000000.0
+			emit(&seleniumtest.CDPMessage{Method: method, SessionID: command.SessionID, Params: params})
+		}
+		console := func(kind string, args ...interface{}) {
+			event("Runtime.consoleAPICalled", map[string]interface{}{"type": kind, "args": args})
+		}
+
+		if command.SessionID == "page-session" {
+			console("warning", map[string]interface{}{"type": "string", "value": "deprecated API"})
+			console("error", map[string]interface{}{"type": "string", "value": "failed to load resource"})
+			console("debug", map[string]interface{}{"type": "string", "value": "state"}, map[string]interface{}{"type": "number", "value": 42})
+			console("log", map[string]interface{}{"type": "object", "description": "Object"}, map[string]interface{}{"type": "undefined"})
+			event("Runtime.exceptionThrown", map[string]interface{}{"exceptionDetails": map[string]interface{}{
+				"text":      "Uncaught",
+				"exception": map[string]interface{}{"type": "object", "description": "TypeError: x is undefined"},
+			}})
+		} else {
+			event("Runtime.exceptionThrown", map[string]interface{}{"exceptionDetails": map[string]interface{}{"text": "Script error."}})
+		}
+
+		return map[string]interface{}{}
+
+	})
+
+	driver := &cdpDriver{selenium.NewRemote(server.URL, selenium.NewCapabilities()), server.WebSocketURL()}
+
+	capture, err := Start(driver)
+	require.NoErrorf(t, err, "Capture should start.")
+	require.Equalf(t, ModeCDP, capture.Mode(), "Chromium driver without BiDi should use the DevTools.")
+
+	//the sessions are forwarded concurrently, so the entries are matched by text
+	entries := make(map[string]*Entry)
+	deadline := time.Now().Add(5 * time.Second)
+	for len(entries) < 6 {
+		collected, err := capture.Entries()
+		require.NoErrorf(t, err, "Entries should be read.")
+		for _, entry := range collected {
+			entries[entry.Text] = entry
+		}
+		require.Truef(t, time.Now().Before(deadline), "Entries of every page should be received.")
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	expected := []struct {
+		text   string
+		level  Level
+		source string
+	}{
+		{"deprecated API", LevelWarning, SourceConsole},
+		{"failed to load resource", LevelError, SourceConsole},
+		{"state 42", LevelDebug, SourceConsole},
+		{"Object undefined", LevelInfo, SourceConsole},
+		{"TypeError: x is undefined", LevelError, SourceJavaScript},
+		{"Script error.", LevelError, SourceJavaScript},
+	}
+
+	for _, e := range expected {
+		entry, ok := entries[e.text]
+		require.Truef(t, ok, "Entry %q should be collected.", e.text)
+		require.Equalf(t, e.level, entry.Level, "Level of %q should be mapped.", e.text)
+		require.Equalf(t, e.source, entry.Source, "Source of %q should be set.", e.text)
+		require.Equalf(t, time.UnixMilli(1700000000000), entry.Timestamp, "Timestamp should be decoded.")
+	}
+
+	require.NoErrorf(t, capture.Stop(), "Capture should stop.")
+	require.NoErrorf(t, capture.Stop(), "Stopping twice should do nothing.")
+
+	collected, err := capture.Entries()
+	require.NoErrorf(t, err, "Entries should remain readable once stopped.")
+	require.Equalf(t, 6, len(collected), "Collected entries should be kept.")
+
+}
+
+func TestCaptureLocalDriver(t *testing.T) {
+
+	server := newBiDiServer()
+	defer server.Close()
+
+	remote := selenium.NewRemote(server.URL, selenium.NewCapabilities())
+	remote.SetSession("session-id", map[string]interface{}{"webSocketUrl": "ws" + strings.TrimPrefix(server.URL, "http")})
+
+	//the drivers of the browser packages, e.g. firefox.Driver, wrap a LocalDriver
+	driver := &selenium.LocalDriver{WebDriver: remote}
+
+	capture, err := Start(driver)
+	require.NoErrorf(t, err, "Capture should start.")
+	require.Equalf(t, ModeBiDi, capture.Mode(), "Local driver session with a webSocketUrl should use BiDi.")
+	require.NoErrorf(t, capture.Stop(), "Capture should stop.")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = Start(driver.WithContext(ctx))
+	require.Truef(t, errors.Is(err, context.Canceled), "Capture should be started with the context of the driver.")
+
+}
@@ -0,0 +1,23 @@
+package selenium
+
+//Cookie is a W3C serialized cookie as read from or added to the current browsing context's document
+type Cookie struct {
+	Name     string   `json:"name"`
+	Value    string   `json:"value"`
+	Path     string   `json:"path,omitempty"`
+	Domain   string   `json:"domain,omitempty"`
+	Secure   bool     `json:"secure,omitempty"`
+	HTTPOnly bool     `json:"httpOnly,omitempty"`
+	Expiry   int64    `json:"expiry,omitempty"`
+	SameSite SameSite `json:"sameSite,omitempty"`
+}
+
+//SameSite indicates whether a cookie is sent with cross-site requests.
+type SameSite string
+
+//Constants for SameSite
+const (
+	SameSiteLax    SameSite = "Lax"
+	SameSiteStrict SameSite = "Strict"
+	SameSiteNone   SameSite = "None"
+)
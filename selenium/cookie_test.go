@@ -0,0 +1,42 @@
+package selenium
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCookies(t *testing.T) {
+
+	server, wd := newCommandServer(t)
+	defer server.Close()
+
+	server.value = []interface{}{
+		map[string]interface{}{"name": "session", "value": "abc", "path": "/", "httpOnly": true, "sameSite": "Lax"},
+	}
+
+	cookies, err := wd.GetCookies()
+	require.NoErrorf(t, err, "Cookies should be read.")
+	require.Equalf(t, []*Cookie{{Name: "session", Value: "abc", Path: "/", HTTPOnly: true, SameSite: SameSiteLax}}, cookies, "Cookies should be decoded.")
+	require.Equalf(t, "GET /session/1/cookie", server.command, "Cookies should be requested.")
+
+	server.value = map[string]interface{}{"name": "a b", "value": "1"}
+
+	cookie, err := wd.GetNamedCookie("a b")
+	require.NoErrorf(t, err, "Named cookie should be read.")
+	require.Equalf(t, "1", cookie.Value, "Named cookie should be decoded.")
+	require.Equalf(t, "GET /session/1/cookie/a b", server.command, "Cookie name should be escaped in the endpoint.")
+
+	server.value = nil
+
+	require.NoErrorf(t, wd.AddCookie(&Cookie{Name: "theme", Value: "dark", Expiry: 1700000000}), "Cookie should be added.")
+	require.Equalf(t, "POST /session/1/cookie", server.command, "Cookie should be posted.")
+	require.Equalf(t, map[string]interface{}{"cookie": map[string]interface{}{"name": "theme", "value": "dark", "expiry": float64(1700000000)}}, server.payload, "Zero cookie fields should be omitted.")
+
+	require.NoErrorf(t, wd.DeleteCookie("theme"), "Cookie should be deleted.")
+	require.Equalf(t, "DELETE /session/1/cookie/theme", server.command, "Named cookie should be deleted.")
+
+	require.NoErrorf(t, wd.DeleteAllCookies(), "Cookies should be deleted.")
+	require.Equalf(t, "DELETE /session/1/cookie", server.command, "Every cookie should be deleted.")
+
+}
@@ -0,0 +1,487 @@
+package selenium
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"image"
+	"image/png"
+	"net/url"
+	"strings"
+)
+
+//Dialect is the wire protocol spoken by the remote end of a session
+type Dialect string
+
+//Dialects negotiated by NewSession
+const (
+	W3CDialect    Dialect = "W3C"
+	LegacyDialect Dialect = "OSS"
+)
+
+//legacyElementIdentifier is the key of web element reference objects in the JSON Wire Protocol
+const legacyElementIdentifier = "ELEMENT"
+
+//legacyErrorCodes maps JSON Wire Protocol status codes to W3C error codes
+var legacyErrorCodes = map[int]ErrorCode{
+	6:   InvalidSessionID,
+	7:   NoSuchElement,
+	8:   NoSuchFrame,
+	9:   UnknownCommand,
+	10:  StaleElementReference,
+	11:  ElementNotInteractable,
+	12:  InvalidElementState,
+	13:  UnknownError,
+	15:  InvalidElementState,
+	17:  JavascriptError,
+	19:  InvalidSelector,
+	21:  Timeout,
+	23:  NoSuchWindow,
+	24:  InvalidCookieDomain,
+	25:  UnableToSetCookie,
+	26:  UnexpectedAlertOpen,
+	27:  NoSuchAlert,
+	28:  ScriptTimeout,
+	29:  InvalidArgument,
+	32:  InvalidSelector,
+	33:  SessionNotCreated,
+	34:  MoveTargetOutOfBounds,
+	51:  InvalidSelector,
+	52:  InvalidSelector,
+	60:  ElementNotInteractable,
+	61:  InvalidArgument,
+	62:  NoSuchCookie,
+	63:  UnableToCaptureScreen,
+	64:  ElementClickIntercepted,
+	405: UnsupportedOperation,
+}
+
+//negotiateDialect returns the dialect of the New Session reply. Legacy remote ends reply with a top-level sessionId and status.
+func negotiateDialect(reply *Reply) Dialect {
+
+	if _, ok := reply.Data["status"].(float64); ok {
+		return LegacyDialect
+	}
+
+	if _, err := reply.GetString("value.sessionId", true); err != nil {
+		if _, ok := reply.Data["sessionId"].(string); ok {
+			return LegacyDialect
+		}
+	}
+
+	return W3CDialect
+
+}
+
+//executeDialect sends a W3C command through executor, translating it when the remote end speaks the legacy dialect
+func executeDialect(ctx context.Context, executor CommandExecutor, dialect Dialect, method Method, endpoint string, data interface{}) (*Reply, error) {
+
+	if dialect == LegacyDialect {
+		return executeLegacy(ctx, executor, method, endpoint, data)
+	}
+
+	return executor.Execute(ctx, method, endpoint, data)
+
+}
+
+//executeLegacy translates a W3C command to the JSON Wire Protocol, sends it through executor and translates the reply back
+func executeLegacy(ctx context.Context, executor CommandExecutor, method Method, endpoint string, data interface{}) (*Reply, error) {
+
+	prefix, command := splitSessionEndpoint(endpoint)
+	data = legacyReferences(data)
+
+	switch {
+
+	case method == GET && command == "/window":
+		return executeLegacyCommand(ctx, executor, GET, prefix+"/window_handle", nil, endpoint)
+
+	case method == GET && command == "/window/handles":
+		return executeLegacyCommand(ctx, executor, GET, prefix+"/window_handles", nil, endpoint)
+
+	case method == POST && command == "/window":
+		if payload, ok := data.(map[string]interface{}); ok {
+			return executeLegacyCommand(ctx, executor, POST, endpoint, map[string]interface{}{"name": payload["handle"], "handle": payload["handle"]}, endpoint)
+		}
+
+	case method == POST && command == "/window/maximize":
+		return executeLegacyCommand(ctx, executor, POST, prefix+"/window/current/maximize", nil, endpoint)
+
+	case method == GET && command == "/window/rect":
+		return executeLegacyRect(ctx, executor, prefix+"/window/current/position", prefix+"/window/current/size", endpoint)
+
+	case method == POST && command == "/window/rect":
+		return executeLegacyWindowRect(ctx, executor, prefix, data, endpoint)
+
+	case method == GET && strings.HasPrefix(command, "/element/") && strings.HasSuffix(command, "/rect"):
+		element := prefix + strings.TrimSuffix(command, "/rect")
+		return executeLegacyRect(ctx, executor, element+"/location", element+"/size", endpoint)
+
+	case method == GET && strings.HasPrefix(command, "/element/") && strings.Contains(command, "/property/"):
+		return executeLegacyCommand(ctx, executor, GET, prefix+strings.Replace(command, "/property/", "/attribute/", 1), nil, endpoint)
+
+	case method == GET && command == "/element/active":
+		return executeLegacyCommand(ctx, executor, POST, endpoint, nil, endpoint)
+
+	case method == POST && strings.HasPrefix(command, "/element/") && strings.HasSuffix(command, "/value"):
+		if payload, ok := data.(map[string]interface{}); ok {
+			if text, ok := payload["text"].(string); ok {
+				chars := make([]string, 0, len(text))
+				for _, c := range text {
+					chars = append(chars, string(c))
+				}
+				return executeLegacyCommand(ctx, executor, POST, endpoint, map[string]interface{}{"value": chars}, endpoint)
+			}
+		}
+
+	case method == GET && strings.HasPrefix(command, "/element/") && strings.HasSuffix(command, "/screenshot"):
+		return executeLegacyElementScreenshot(ctx, executor, prefix, strings.TrimSuffix(command, "/screenshot"), endpoint)
+
+	case method == GET && strings.HasPrefix(command, "/cookie/"):
+		return executeLegacyNamedCookie(ctx, executor, prefix, strings.TrimPrefix(command, "/cookie/"), endpoint)
+
+	case method == POST && command == "/execute/sync":
+		return executeLegacyCommand(ctx, executor, POST, prefix+"/execute", data, endpoint)
+
+	case method == POST && command == "/execute/async":
+		return executeLegacyCommand(ctx, executor, POST, prefix+"/execute_async", data, endpoint)
+
+	case method == GET && command == "/alert/text":
+		return executeLegacyCommand(ctx, executor, GET, prefix+"/alert_text", nil, endpoint)
+
+	case method == POST && command == "/alert/text":
+		return executeLegacyCommand(ctx, executor, POST, prefix+"/alert_text", data, endpoint)
+
+	case method == POST && command == "/alert/accept":
+		return executeLegacyCommand(ctx, executor, POST, prefix+"/accept_alert", nil, endpoint)
+
+	case method == POST && command == "/alert/dismiss":
+		return executeLegacyCommand(ctx, executor, POST, prefix+"/dismiss_alert", nil, endpoint)
+
+	case method == POST && command == "/timeouts":
+		return executeLegacyTimeouts(ctx, executor, endpoint, data)
+
+	}
+
+	return executeLegacyCommand(ctx, executor, method, endpoint, data, endpoint)
+
+}
+
+//executeLegacyCommand sends a JSON Wire Protocol command and returns its reply in the W3C form expected by the W3C endpoint
+func executeLegacyCommand(ctx context.Context, executor CommandExecutor, method Method, endpoint string, data interface{}, w3cEndpoint string) (*Reply, error) {
+
+	reply, err := executor.Execute(ctx, method, endpoint, data)
+	if err != nil {
+		return nil, err
+	}
+
+	reply = legacyReply(reply)
+	reply.Endpoint = w3cEndpoint
+
+	return reply, nil
+
+}
+
+//executeLegacyRect merges the replies of the legacy location and size commands into a W3C rect
+func executeLegacyRect(ctx context.Context, executor CommandExecutor, positionEndpoint string, sizeEndpoint string, w3cEndpoint string) (*Reply, error) {
+
+	position, err := executeLegacyCommand(ctx, executor, GET, positionEndpoint, nil, w3cEndpoint)
+	if err != nil || position.StatusCode != 200 {
+		return position, err
+	}
+
+	size, err := executeLegacyCommand(ctx, executor, GET, sizeEndpoint, nil, w3cEndpoint)
+	if err != nil || size.StatusCode != 200 {
+		return size, err
+	}
+
+	rect := make(map[string]interface{})
+	for _, reply := range []*Reply{position, size} {
+		if value, ok := reply.Data["value"].(map[string]interface{}); ok {
+			for key, v := range value {
+				rect[key] = v
+			}
+		}
+	}
+
+	size.Data = map[string]interface{}{"value": rect}
+
+	return size, nil
+
+}
+
+func executeLegacyWindowRect(ctx context.Context, executor CommandExecutor, prefix string, data interface{}, w3cEndpoint string) (*Reply, error) {
+
+	request, err := normalizeRequest(data)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, _ := request.(map[string]interface{})
+
+	if payload["width"] != nil || payload["height"] != nil {
+		reply, err := executeLegacyCommand(ctx, executor, POST, prefix+"/window/current/size", map[string]interface{}{"width": payload["width"], "height": payload["height"]}, w3cEndpoint)
+		if err != nil || reply.StatusCode != 200 {
+			return reply, err
+		}
+	}
+
+	if payload["x"] != nil || payload["y"] != nil {
+		reply, err := executeLegacyCommand(ctx, executor, POST, prefix+"/window/current/position", map[string]interface{}{"x": payload["x"], "y": payload["y"]}, w3cEndpoint)
+		if err != nil || reply.StatusCode != 200 {
+			return reply, err
+		}
+	}
+
+	return executeLegacyRect(ctx, executor, prefix+"/window/current/position", prefix+"/window/current/size", w3cEndpoint)
+
+}
+
+//executeLegacyTimeouts sets each W3C timeout with its own legacy command
+func executeLegacyTimeouts(ctx context.Context, executor CommandExecutor, endpoint string, data interface{}) (*Reply, error) {
+
+	request, err := normalizeRequest(data)
+	if err != nil {
+		return nil, err
+	}
+
+	payload, _ := request.(map[string]interface{})
+
+	types := []struct{ w3c, legacy string }{{"script", "script"}, {"pageLoad", "page load"}, {"implicit", "implicit"}}
+
+	reply := &Reply{StatusCode: 200, Data: map[string]interface{}{"value": nil}, Method: POST, Endpoint: endpoint}
+
+	for _, t := range types {
+
+		ms, ok := payload[t.w3c]
+		if !ok {
+			continue
+		}
+
+		reply, err = executeLegacyCommand(ctx, executor, POST, endpoint, map[string]interface{}{"type": t.legacy, "ms": ms}, endpoint)
+		if err != nil || reply.StatusCode != 200 {
+			return reply, err
+		}
+
+	}
+
+	return reply, nil
+
+}
+
+//executeLegacyNamedCookie looks the cookie up in all the cookies of the page, as the legacy protocol cannot get a cookie by name
+func executeLegacyNamedCookie(ctx context.Context, executor CommandExecutor, prefix string, escapedName string, w3cEndpoint string) (*Reply, error) {
+
+	name, err := url.PathUnescape(escapedName)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := executeLegacyCommand(ctx, executor, GET, prefix+"/cookie", nil, w3cEndpoint)
+	if err != nil || reply.StatusCode != 200 {
+		return reply, err
+	}
+
+	cookies, _ := reply.Data["value"].([]interface{})
+	for _, item := range cookies {
+		if cookie, ok := item.(map[string]interface{}); ok && cookie["name"] == name {
+			reply.Data = map[string]interface{}{"value": cookie}
+			return reply, nil
+		}
+	}
+
+	value := map[string]interface{}{"error": string(NoSuchCookie), "message": "no cookie named " + name}
+
+	return &Reply{StatusCode: 404, Data: map[string]interface{}{"value": value}, Method: GET, Endpoint: w3cEndpoint}, nil
+
+}
+
+//executeLegacyElementScreenshot sends the element screenshot command, which only some legacy remote ends know.
+//Otherwise the page screenshot is cropped to the element, assuming one device pixel per CSS pixel.
+func executeLegacyElementScreenshot(ctx context.Context, executor CommandExecutor, prefix string, element string, w3cEndpoint string) (*Reply, error) {
+
+	reply, err := executeLegacyCommand(ctx, executor, GET, prefix+element+"/screenshot", nil, w3cEndpoint)
+	if err != nil || reply.StatusCode == 200 || !legacyUnknownCommand(reply) {
+		return reply, err
+	}
+
+	rect, err := executeLegacyRect(ctx, executor, prefix+element+"/location_in_view", prefix+element+"/size", w3cEndpoint)
+	if err != nil || rect.StatusCode != 200 {
+		return rect, err
+	}
+
+	screenshot, err := executeLegacyCommand(ctx, executor, GET, prefix+"/screenshot", nil, w3cEndpoint)
+	if err != nil || screenshot.StatusCode != 200 {
+		return screenshot, err
+	}
+
+	encoded, _ := screenshot.Data["value"].(string)
+	bounds, _ := rect.Data["value"].(map[string]interface{})
+
+	cropped, err := cropScreenshot(encoded, bounds)
+	if err != nil {
+		return nil, err
+	}
+
+	screenshot.Data = map[string]interface{}{"value": cropped}
+
+	return screenshot, nil
+
+}
+
+//legacyUnknownCommand reports whether a failed legacy reply means the remote end does not implement the command
+func legacyUnknownCommand(reply *Reply) bool {
+
+	if reply.StatusCode == 404 || reply.StatusCode == 405 {
+		return true
+	}
+
+	return errors.Is(reply.GetError(), UnknownCommand)
+
+}
+
+//cropScreenshot crops the base64 encoded PNG screenshot to the x, y, width and height of rect
+func cropScreenshot(encoded string, rect map[string]interface{}) (string, error) {
+
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+
+	page, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+
+	x, _ := rect["x"].(float64)
+	y, _ := rect["y"].(float64)
+	width, _ := rect["width"].(float64)
+	height, _ := rect["height"].(float64)
+
+	bounds := image.Rect(int(x), int(y), int(x+width), int(y+height)).Intersect(page.Bounds())
+
+	cropper, ok := page.(interface {
+		SubImage(r image.Rectangle) image.Image
+	})
+	if !ok {
+		return "", errors.New("could not crop screenshot")
+	}
+
+	buffer := new(bytes.Buffer)
+	err = png.Encode(buffer, cropper.SubImage(bounds))
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(buffer.Bytes()), nil
+
+}
+
+//legacyReply converts a JSON Wire Protocol reply to the W3C form, turning a non zero status into a W3C error
+func legacyReply(reply *Reply) *Reply {
+
+	status, ok := reply.Data["status"].(float64)
+	if !ok {
+		return reply
+	}
+
+	translated := &Reply{StatusCode: reply.StatusCode, Method: reply.Method, Endpoint: reply.Endpoint}
+
+	if status == 0 {
+		translated.Data = map[string]interface{}{"value": w3cReferences(reply.Data["value"])}
+		return translated
+	}
+
+	code, ok := legacyErrorCodes[int(status)]
+	if !ok {
+		code = UnknownError
+	}
+
+	value := map[string]interface{}{"error": string(code), "message": ""}
+	if legacy, ok := reply.Data["value"].(map[string]interface{}); ok {
+		if message, ok := legacy["message"].(string); ok {
+			value["message"] = message
+		}
+		if alert, ok := legacy["alert"].(map[string]interface{}); ok {
+			value["data"] = map[string]interface{}{"text": alert["text"]}
+		}
+	}
+
+	if translated.StatusCode == 200 {
+		translated.StatusCode = 500
+	}
+	translated.Data = map[string]interface{}{"value": value}
+
+	return translated
+
+}
+
+//legacyReferences adds the legacy key to the web element references of a command payload
+func legacyReferences(data interface{}) interface{} {
+
+	switch value := data.(type) {
+
+	case map[string]interface{}:
+		converted := make(map[string]interface{}, len(value)+1)
+		for key, item := range value {
+			converted[key] = legacyReferences(item)
+		}
+		if id, ok := value[WebElementIdentifier]; ok && len(value) == 1 {
+			converted[legacyElementIdentifier] = id
+		}
+		return converted
+
+	case []interface{}:
+		converted := make([]interface{}, len(value))
+		for i, item := range value {
+			converted[i] = legacyReferences(item)
+		}
+		return converted
+
+	}
+
+	return data
+
+}
+
+//w3cReferences replaces the legacy web element references of a reply value by W3C ones
+func w3cReferences(data interface{}) interface{} {
+
+	switch value := data.(type) {
+
+	case map[string]interface{}:
+		if id, ok := value[legacyElementIdentifier].(string); ok && len(value) == 1 {
+			return map[string]interface{}{WebElementIdentifier: id}
+		}
+		converted := make(map[string]interface{}, len(value))
+		for key, item := range value {
+			converted[key] = w3cReferences(item)
+		}
+		return converted
+
+	case []interface{}:
+		converted := make([]interface{}, len(value))
+		for i, item := range value {
+			converted[i] = w3cReferences(item)
+		}
+		return converted
+
+	}
+
+	return data
+
+}
+
+//splitSessionEndpoint splits "/session/{id}/command" into "/session/{id}" and "/command"
+func splitSessionEndpoint(endpoint string) (string, string) {
+
+	segments := strings.SplitN(endpoint, "/", 4)
+	if len(segments) < 3 || segments[1] != "session" {
+		return "", endpoint
+	}
+
+	prefix := strings.Join(segments[:3], "/")
+
+	return prefix, strings.TrimPrefix(endpoint, prefix)
+
+}
@@ -0,0 +1,154 @@
+package selenium
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"image"
+	"image/png"
+	"testing"
+
+	"./by"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLegacyDialect(t *testing.T) {
+
+	commands := make([]string, 0)
+
+	executor := CommandExecutorFunc(func(ctx context.Context, method Method, endpoint string, data interface{}) (*Reply, error) {
+
+		commands = append(commands, string(method)+" "+endpoint)
+
+		switch endpoint {
+		case "/session":
+			return &Reply{StatusCode: 200, Data: map[string]interface{}{"sessionId": "1", "status": float64(0), "value": map[string]interface{}{"browserName": "chrome"}}}, nil
+		case "/session/1/window_handle":
+			return &Reply{StatusCode: 200, Data: map[string]interface{}{"sessionId": "1", "status": float64(0), "value": "CDwindow-1"}}, nil
+		case "/session/1/element":
+			if data.(map[string]interface{})["value"] == "#missing" {
+				return &Reply{StatusCode: 200, Data: map[string]interface{}{"sessionId": "1", "status": float64(7), "value": map[string]interface{}{"message": "no such element"}}}, nil
+			}
+			return &Reply{StatusCode: 200, Data: map[string]interface{}{"sessionId": "1", "status": float64(0), "value": map[string]interface{}{"ELEMENT": "0.1"}}}, nil
+		}
+
+		return &Reply{StatusCode: 200, Data: map[string]interface{}{"sessionId": "1", "status": float64(0), "value": nil}}, nil
+
+	})
+
+	wd := NewRemoteWithExecutor(executor, NewCapabilities())
+
+	session, err := wd.NewSession()
+	require.NoErrorf(t, err, "Legacy session should be created.")
+	require.Equalf(t, LegacyDialect, session.GetDialect(), "Dialect should be negotiated from the reply.")
+	require.Equalf(t, "1", session.GetID(), "Session ID should be read from the top-level sessionId.")
+
+	handle, err := wd.GetWindowHandle()
+	require.NoErrorf(t, err, "GetWindowHandle should succeed.")
+	require.Equalf(t, "CDwindow-1", handle, "Window handle should be read from the legacy reply.")
+
+	element, err := wd.FindElement(by.ID("button"))
+	require.NoErrorf(t, err, "FindElement should succeed.")
+	require.Equalf(t, WebElementIdentifier, element.(WebElementInfo).GetID(), "Legacy references should be translated to W3C ones.")
+
+	_, err = wd.FindElement(by.ID("missing"))
+	require.Truef(t, errors.Is(err, NoSuchElement), "Legacy status codes should be mapped to W3C errors.")
+
+	require.NoErrorf(t, wd.SetTimeouts(&Timeouts{Script: 100, Implicit: 200}), "SetTimeouts should succeed.")
+
+	require.Equalf(t, []string{
+		"POST /session",
+		"GET /session/1/window_handle",
+		"POST /session/1/element",
+		"POST /session/1/element",
+		"POST /session/1/timeouts",
+		"POST /session/1/timeouts",
+	}, commands, "Commands should be translated to the legacy protocol.")
+
+}
+
+func TestLegacyDialectCommands(t *testing.T) {
+
+	page := new(bytes.Buffer)
+	require.NoErrorf(t, png.Encode(page, image.NewRGBA(image.Rect(0, 0, 4, 4))), "Page screenshot should be encoded.")
+
+	legacy := func(value interface{}) *Reply {
+		return &Reply{StatusCode: 200, Data: map[string]interface{}{"sessionId": "1", "status": float64(0), "value": value}}
+	}
+
+	payloads := make(map[string][]interface{})
+
+	executor := CommandExecutorFunc(func(ctx context.Context, method Method, endpoint string, data interface{}) (*Reply, error) {
+
+		payloads[string(method)+" "+endpoint] = append(payloads[string(method)+" "+endpoint], data)
+
+		switch endpoint {
+		case "/session":
+			return legacy(map[string]interface{}{"browserName": "chrome"}), nil
+		case "/session/1/cookie":
+			return legacy([]interface{}{map[string]interface{}{"name": "a b", "value": "1"}}), nil
+		case "/session/1/element/0.1/screenshot":
+			return &Reply{StatusCode: 200, Data: map[string]interface{}{"sessionId": "1", "status": float64(9), "value": map[string]interface{}{"message": "unknown command"}}}, nil
+		case "/session/1/element/0.1/location_in_view":
+			return legacy(map[string]interface{}{"x": float64(1), "y": float64(1)}), nil
+		case "/session/1/element/0.1/size":
+			return legacy(map[string]interface{}{"width": float64(2), "height": float64(3)}), nil
+		case "/session/1/screenshot":
+			return legacy(base64.StdEncoding.EncodeToString(page.Bytes())), nil
+		}
+
+		return legacy(nil), nil
+
+	})
+
+	wd := NewRemoteWithExecutor(executor, NewCapabilities())
+
+	_, err := wd.NewSession()
+	require.NoErrorf(t, err, "Legacy session should be created.")
+
+	element := &webElement{id: WebElementIdentifier, value: "0.1", driver: wd}
+
+	require.NoErrorf(t, wd.ElementSendKeys(element, "hé"), "ElementSendKeys should succeed.")
+	require.Equalf(t, []interface{}{map[string]interface{}{"value": []string{"h", "é"}}}, payloads["POST /session/1/element/0.1/value"], "Keys should be sent as a list of characters.")
+
+	require.NoErrorf(t, wd.SetTimeouts(&Timeouts{Script: 100, PageLoad: 300, Implicit: 200}), "SetTimeouts should succeed.")
+	require.Equalf(t, []interface{}{
+		map[string]interface{}{"type": "script", "ms": float64(100)},
+		map[string]interface{}{"type": "page load", "ms": float64(300)},
+		map[string]interface{}{"type": "implicit", "ms": float64(200)},
+	}, payloads["POST /session/1/timeouts"], "Each timeout should be set with its own legacy command.")
+
+	cookie, err := wd.GetNamedCookie("a b")
+	require.NoErrorf(t, err, "GetNamedCookie should succeed.")
+	require.Equalf(t, "1", cookie.Value, "Named cookie should be found among all cookies.")
+
+	_, err = wd.GetNamedCookie("missing")
+	require.Truef(t, errors.Is(err, NoSuchCookie), "Missing cookies should raise a no such cookie error.")
+
+	screenshot, err := wd.TakeElementScreenshot(element)
+	require.NoErrorf(t, err, "TakeElementScreenshot should succeed.")
+	cropped, err := screenshot.Image()
+	require.NoErrorf(t, err, "Element screenshot should be a PNG image.")
+	require.Equalf(t, image.Pt(2, 3), cropped.Bounds().Size(), "Page screenshot should be cropped to the element.")
+
+}
+
+func TestW3CDialect(t *testing.T) {
+
+	var payload map[string]interface{}
+
+	executor := CommandExecutorFunc(func(ctx context.Context, method Method, endpoint string, data interface{}) (*Reply, error) {
+		payload = data.(map[string]interface{})
+		return &Reply{StatusCode: 200, Data: map[string]interface{}{"value": map[string]interface{}{"sessionId": "2", "capabilities": map[string]interface{}{}}}}, nil
+	})
+
+	wd := NewRemoteWithExecutor(executor, NewCapabilities())
+
+	session, err := wd.NewSession()
+	require.NoErrorf(t, err, "W3C session should be created.")
+	require.Equalf(t, W3CDialect, session.GetDialect(), "Dialect should be negotiated from the reply.")
+	require.Containsf(t, payload, "capabilities", "W3C capabilities should be sent.")
+	require.Containsf(t, payload, "desiredCapabilities", "Legacy capabilities should be sent.")
+
+}
@@ -0,0 +1,147 @@
+package selenium
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//ErrBinaryNotFound is returned by FindBinary when no executable is found
+var ErrBinaryNotFound = errors.New("binary not found")
+
+//versionTimeout bounds the time spent running a binary with --version
+const versionTimeout = 10 * time.Second
+
+//FindBinary returns the path of an executable, looked up in order from the explicit path, the environment variable env
+//and the names on $PATH
+func FindBinary(path string, env string, names ...string) (string, error) {
+
+	if path != "" {
+		return checkExecutable(path)
+	}
+
+	if env != "" {
+		if path := os.Getenv(env); path != "" {
+			return checkExecutable(path)
+		}
+	}
+
+	for _, name := range names {
+		if path, err := exec.LookPath(name); err == nil {
+			return path, nil
+		}
+	}
+
+	if env != "" {
+		return "", fmt.Errorf("%w: set %s or add one of %s to $PATH", ErrBinaryNotFound, env, strings.Join(names, ", "))
+	}
+
+	return "", fmt.Errorf("%w: add one of %s to $PATH", ErrBinaryNotFound, strings.Join(names, ", "))
+
+}
+
+func checkExecutable(path string) (string, error) {
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrBinaryNotFound, err)
+	}
+
+	if info.IsDir() {
+		return "", fmt.Errorf("%w: %s is a directory", ErrBinaryNotFound, path)
+	}
+
+	if !isExecutable(info) {
+		return "", fmt.Errorf("%w: %s is not executable", ErrBinaryNotFound, path)
+	}
+
+	return path, nil
+
+}
+
+//Version is the version of a driver or browser binary
+type Version struct {
+	Major int
+	Minor int
+	Build int
+	Patch int
+	Raw   string
+}
+
+func (version *Version) String() string {
+	return version.Raw
+}
+
+var versionPattern = regexp.MustCompile(`\d+(?:\.\d+)+`)
+
+//ParseVersion parses the first dotted version number of the --version output of a binary,
+//e.g. "ChromeDriver 120.0.6099.109 (3419140...)" or "Mozilla Firefox 121.0"
+func ParseVersion(output string) (*Version, error) {
+
+	raw := versionPattern.FindString(output)
+	if raw == "" {
+		return nil, fmt.Errorf("could not parse version: %q", strings.TrimSpace(output))
+	}
+
+	version := &Version{Raw: raw}
+	fields := []*int{&version.Major, &version.Minor, &version.Build, &version.Patch}
+
+	for i, part := range strings.Split(raw, ".") {
+		if i >= len(fields) {
+			break
+		}
+		number, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse version: %q", raw)
+		}
+		*fields[i] = number
+	}
+
+	return version, nil
+
+}
+
+//BinaryVersion runs the binary at path with --version and parses its output
+func BinaryVersion(path string) (*Version, error) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), versionTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, path, "--version").CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("could not get version of %s: %v: %s", path, err, strings.TrimSpace(string(output)))
+	}
+
+	return ParseVersion(string(output))
+
+}
+
+//VersionMismatchError is returned when a driver does not support the major version of its browser
+type VersionMismatchError struct {
+	Driver         string
+	DriverVersion  *Version
+	Browser        string
+	BrowserVersion *Version
+}
+
+func (e *VersionMismatchError) Error() string {
+	return fmt.Sprintf("%s %s only supports %s %d but %s %s was found; install the matching driver version",
+		e.Driver, e.DriverVersion, e.Browser, e.DriverVersion.Major, e.Browser, e.BrowserVersion)
+}
+
+//CheckMajorVersions returns a VersionMismatchError if the driver and browser major versions differ
+func CheckMajorVersions(driver string, driverVersion *Version, browser string, browserVersion *Version) error {
+
+	if driverVersion.Major != browserVersion.Major {
+		return &VersionMismatchError{Driver: driver, DriverVersion: driverVersion, Browser: browser, BrowserVersion: browserVersion}
+	}
+
+	return nil
+
+}
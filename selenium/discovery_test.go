@@ -0,0 +1,69 @@
+package selenium
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVersion(t *testing.T) {
+
+	version, err := ParseVersion("ChromeDriver 120.0.6099.109 (3419140ab665596f21b385ce136419fde0924272-refs/branch-heads/6099@{#1483})\n")
+	require.NoErrorf(t, err, "ChromeDriver version should be parsed.")
+	require.Equalf(t, &Version{Major: 120, Minor: 0, Build: 6099, Patch: 109, Raw: "120.0.6099.109"}, version, "Every version part should be parsed.")
+
+	version, err = ParseVersion("Mozilla Firefox 121.0")
+	require.NoErrorf(t, err, "Firefox version should be parsed.")
+	require.Equalf(t, 121, version.Major, "Major version should be parsed.")
+
+	_, err = ParseVersion("command not found")
+	require.Errorf(t, err, "Output without a version should not be parsed.")
+
+}
+
+func TestCheckMajorVersions(t *testing.T) {
+
+	driver, _ := ParseVersion("ChromeDriver 119.0.6045.105")
+	browser, _ := ParseVersion("Google Chrome 120.0.6099.109")
+
+	err := CheckMajorVersions("chromedriver", driver, "Chrome", browser)
+
+	mismatch := new(VersionMismatchError)
+	require.Truef(t, errors.As(err, &mismatch), "Different major versions should be reported.")
+	require.Containsf(t, err.Error(), "chromedriver 119.0.6045.105 only supports Chrome 119", "Error should name both versions.")
+
+	require.NoErrorf(t, CheckMajorVersions("chromedriver", browser, "Chrome", browser), "Equal major versions should be accepted.")
+
+}
+
+func TestFindBinary(t *testing.T) {
+
+	dir := t.TempDir()
+	binary := filepath.Join(dir, "fakedriver")
+	require.NoErrorf(t, ioutil.WriteFile(binary, []byte("#!/bin/sh\n"), 0755), "Fake binary should be written.")
+
+	path, err := FindBinary(binary, "SELENIUM_FAKEDRIVER")
+	require.NoErrorf(t, err, "Explicit path should be used.")
+	require.Equalf(t, binary, path, "Explicit path should be returned.")
+
+	t.Setenv("SELENIUM_FAKEDRIVER", binary)
+	path, err = FindBinary("", "SELENIUM_FAKEDRIVER", "fakedriver-not-on-path")
+	require.NoErrorf(t, err, "Environment variable should be used.")
+	require.Equalf(t, binary, path, "Path from the environment should be returned.")
+
+	notExecutable := filepath.Join(dir, "notexecutable")
+	require.NoErrorf(t, ioutil.WriteFile(notExecutable, []byte("#!/bin/sh\n"), 0644), "Fake binary should be written.")
+	_, err = FindBinary(notExecutable, "SELENIUM_FAKEDRIVER")
+	if runtime.GOOS != "windows" {
+		require.Truef(t, errors.Is(err, ErrBinaryNotFound), "Binary without execute permission should be reported.")
+	}
+
+	t.Setenv("SELENIUM_FAKEDRIVER", "")
+	_, err = FindBinary("", "SELENIUM_FAKEDRIVER", "fakedriver-not-on-path")
+	require.Truef(t, errors.Is(err, ErrBinaryNotFound), "Missing binary should be reported.")
+
+}
@@ -0,0 +1,26 @@
+package edge
+
+import (
+	"errors"
+
+	"../../selenium"
+)
+
+func init() {
+	selenium.RegisterBrowser("edge", selenium.Browser{
+		NewOptions: func() selenium.Options { return new(EdgeOptions) },
+		Start:      start,
+	})
+}
+
+//start starts msedgedriver, found from the MSEDGEDRIVER environment variable or $PATH, on a free port
+func start(options selenium.Options, middlewares ...selenium.Middleware) (selenium.Driver, error) {
+
+	edgeOptions, ok := options.(*EdgeOptions)
+	if !ok {
+		return nil, errors.New("edge requires *edge.EdgeOptions")
+	}
+
+	return Driver("", 0, edgeOptions, middlewares...)
+
+}
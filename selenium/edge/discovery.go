@@ -0,0 +1,45 @@
+package edge
+
+import (
+	"../../selenium"
+	"../chrome"
+)
+
+//DriverEnv is the environment variable naming the msedgedriver binary
+const DriverEnv = "MSEDGEDRIVER"
+
+//browserNames are the Edge binaries looked up on $PATH
+var browserNames = []string{"microsoft-edge", "microsoft-edge-stable", "microsoft-edge-beta", "microsoft-edge-dev"}
+
+//FindDriver returns the path of msedgedriver from the explicit path, the MSEDGEDRIVER environment variable or $PATH
+func FindDriver(path string) (string, error) {
+	return selenium.FindBinary(path, DriverEnv, "msedgedriver")
+}
+
+//FindBrowser returns the path of Edge from the explicit binary or $PATH
+func FindBrowser(binary string) (string, error) {
+	return selenium.FindBinary(binary, "", browserNames...)
+}
+
+//CheckVersions returns a selenium.VersionMismatchError if msedgedriver at driverPath does not support the major version of Edge at browserPath
+func CheckVersions(driverPath string, browserPath string) error {
+	return chrome.CheckChromiumVersions("msedgedriver", driverPath, "Edge", browserPath)
+}
+
+//checkBrowserVersion fails fast when the Edge used by options is not supported by msedgedriver.
+//The check is skipped when Edge cannot be found or does not report its version.
+func checkBrowserVersion(driverPath string, options *EdgeOptions) error {
+
+	binary := ""
+	if options != nil {
+		binary = options.Binary
+	}
+
+	browserPath, err := FindBrowser(binary)
+	if err != nil {
+		return nil
+	}
+
+	return chrome.CheckChromiumBrowserVersion("msedgedriver", driverPath, "Edge", browserPath)
+
+}
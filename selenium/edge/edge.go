@@ -0,0 +1,38 @@
+package edge
+
+import (
+	"../../selenium"
+	"../chrome"
+)
+
+//Driver starts the msedgedriver server on the specified port, or a free port if 0, and returns a WebDriver implementation.
+//When path is empty msedgedriver is looked up from the MSEDGEDRIVER environment variable and $PATH.
+//Every command, including the new session, is sent through middlewares.
+func Driver(path string, port int, options *EdgeOptions, middlewares ...selenium.Middleware) (chrome.ChromiumDriver, error) {
+
+	path, err := FindDriver(path)
+	if err != nil {
+		return nil, err
+	}
+
+	err = checkBrowserVersion(path, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return DriverWithService(selenium.NewService(path, port), options, middlewares...)
+
+}
+
+//DriverWithService starts service, e.g. configured to capture the msedgedriver logs, and returns a WebDriver implementation.
+//The driver shares the Chromium specific behaviors of the chrome package.
+func DriverWithService(service *selenium.Service, options *EdgeOptions, middlewares ...selenium.Middleware) (chrome.ChromiumDriver, error) {
+
+	driver, err := chrome.StartChromium(service, "ms", chrome.NewChromiumCapabilities("MicrosoftEdge", "ms:edgeOptions", options.chromium()), middlewares...)
+	if err != nil {
+		return nil, err
+	}
+
+	return driver, nil
+
+}
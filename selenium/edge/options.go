@@ -0,0 +1,20 @@
+package edge
+
+import "../chrome"
+
+//EdgeOptions is an implementation of the ms:edgeOptions key included in desired capabilities.
+//Edge being Chromium based, it accepts the same options as Chrome.
+type EdgeOptions struct {
+	chrome.ChromeOptions
+}
+
+//chromium returns the Chromium options sent under ms:edgeOptions
+func (options *EdgeOptions) chromium() *chrome.ChromeOptions {
+
+	if options == nil {
+		return nil
+	}
+
+	return &options.ChromeOptions
+
+}
@@ -0,0 +1,24 @@
+package edge
+
+import (
+	"encoding/json"
+	"testing"
+
+	"../chrome"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapabilitiesJSON(t *testing.T) {
+
+	options := new(EdgeOptions)
+	options.SetHeadless(true)
+	options.AddArgs("--disable-gpu")
+
+	data, err := json.Marshal(chrome.NewChromiumCapabilities("MicrosoftEdge", "ms:edgeOptions", options.chromium()))
+	require.NoErrorf(t, err, "Capabilities should be encoded.")
+	require.JSONEqf(t, `{
+		"browserName": "MicrosoftEdge",
+		"ms:edgeOptions": {"args": ["--headless=new", "--disable-gpu"]}
+	}`, string(data), "Edge options should be sent under ms:edgeOptions.")
+
+}
@@ -1,7 +1,527 @@
 package selenium
 
+import "fmt"
+
+//Error is a W3C error returned by the remote end. It unwraps to its ErrorCode so that
+//failures can be matched with errors.Is(err, selenium.NoSuchElement). Replies carrying a known
+//code are wrapped in a type per code, such as *NoSuchElementError, for use with errors.As.
 type Error struct {
-	Error      string `json:"error,omitempty"`
-	Message    string `json:"message,omitempty"`
-	Stacktrace string `json:"stacktrace,omitempty"`
+	Code       ErrorCode              `json:"error,omitempty"`
+	Message    string                 `json:"message,omitempty"`
+	Stacktrace string                 `json:"stacktrace,omitempty"`
+	Data       map[string]interface{} `json:"data,omitempty"`
+	StatusCode int                    `json:"-"`
+	Method     Method                 `json:"-"`
+	Endpoint   string                 `json:"-"`
+}
+
+func (e *Error) Error() string {
+	if e.Endpoint == "" {
+		return fmt.Sprintf("%s: %s", e.Code, e.Message)
+	}
+	return fmt.Sprintf("%s: %s (%s %s)", e.Code, e.Message, e.Method, e.Endpoint)
+}
+
+//Unwrap returns the error code of the remote error
+func (e *Error) Unwrap() error {
+	return e.Code
+}
+
+//ErrorCode is the W3C error code identifying the kind of a remote error
+type ErrorCode string
+
+func (code ErrorCode) Error() string {
+	return string(code)
+}
+
+//Constants for ErrorCode
+const (
+	ElementClickIntercepted ErrorCode = "element click intercepted"
+	ElementNotInteractable  ErrorCode = "element not interactable"
+	InsecureCertificate     ErrorCode = "insecure certificate"
+	InvalidArgument         ErrorCode = "invalid argument"
+	InvalidCookieDomain     ErrorCode = "invalid cookie domain"
+	InvalidElementState     ErrorCode = "invalid element state"
+	InvalidSelector         ErrorCode = "invalid selector"
+	InvalidSessionID        ErrorCode = "invalid session id"
+	JavascriptError         ErrorCode = "javascript error"
+	MoveTargetOutOfBounds   ErrorCode = "move target out of bounds"
+	NoSuchAlert             ErrorCode = "no such alert"
+	NoSuchCookie            ErrorCode = "no such cookie"
+	NoSuchElement           ErrorCode = "no such element"
+	NoSuchFrame             ErrorCode = "no such frame"
+	NoSuchWindow            ErrorCode = "no such window"
+	NoSuchShadowRoot        ErrorCode = "no such shadow root"
+	ScriptTimeout           ErrorCode = "script timeout"
+	SessionNotCreated       ErrorCode = "session not created"
+	StaleElementReference   ErrorCode = "stale element reference"
+	DetachedShadowRoot      ErrorCode = "detached shadow root"
+	Timeout                 ErrorCode = "timeout"
+	UnableToSetCookie       ErrorCode = "unable to set cookie"
+	UnableToCaptureScreen   ErrorCode = "unable to capture screen"
+	UnexpectedAlertOpen     ErrorCode = "unexpected alert open"
+	UnknownCommand          ErrorCode = "unknown command"
+	UnknownError            ErrorCode = "unknown error"
+	UnknownMethod           ErrorCode = "unknown method"
+	UnsupportedOperation    ErrorCode = "unsupported operation"
+)
+
+//UnexpectedAlertOpenError is returned when a user prompt blocked the execution of a command
+type UnexpectedAlertOpenError struct {
+	Remote *Error
+	Text   string
+}
+
+func (e *UnexpectedAlertOpenError) Error() string {
+	if e.Text == "" {
+		return e.Remote.Error()
+	}
+	return fmt.Sprintf("%s (alert text: %s)", e.Remote.Error(), e.Text)
+}
+
+//Unwrap returns the underlying remote error
+func (e *UnexpectedAlertOpenError) Unwrap() error {
+	return e.Remote
+}
+
+//newTypedError wraps a remote error in the type for its error code
+func newTypedError(remoteError *Error) error {
+
+	switch remoteError.Code {
+	case ElementClickIntercepted:
+		return &ElementClickInterceptedError{Remote: remoteError}
+	case ElementNotInteractable:
+		return &ElementNotInteractableError{Remote: remoteError}
+	case InsecureCertificate:
+		return &InsecureCertificateError{Remote: remoteError}
+	case InvalidArgument:
+		return &InvalidArgumentError{Remote: remoteError}
+	case InvalidCookieDomain:
+		return &InvalidCookieDomainError{Remote: remoteError}
+	case InvalidElementState:
+		return &InvalidElementStateError{Remote: remoteError}
+	case InvalidSelector:
+		return &InvalidSelectorError{Remote: remoteError}
+	case InvalidSessionID:
+		return &InvalidSessionIDError{Remote: remoteError}
+	case JavascriptError:
+		return &JavascriptErrorError{Remote: remoteError}
+	case MoveTargetOutOfBounds:
+		return &MoveTargetOutOfBoundsError{Remote: remoteError}
+	case NoSuchAlert:
+		return &NoSuchAlertError{Remote: remoteError}
+	case NoSuchCookie:
+		return &NoSuchCookieError{Remote: remoteError}
+	case NoSuchElement:
+		return &NoSuchElementError{Remote: remoteError}
+	case NoSuchFrame:
+		return &NoSuchFrameError{Remote: remoteError}
+	case NoSuchWindow:
+		return &NoSuchWindowError{Remote: remoteError}
+	case NoSuchShadowRoot:
+		return &NoSuchShadowRootError{Remote: remoteError}
+	case ScriptTimeout:
+		return &ScriptTimeoutError{Remote: remoteError}
+	case SessionNotCreated:
+		return &SessionNotCreatedError{Remote: remoteError}
+	case StaleElementReference:
+		return &StaleElementReferenceError{Remote: remoteError}
+	case DetachedShadowRoot:
+		return &DetachedShadowRootError{Remote: remoteError}
+	case Timeout:
+		return &TimeoutError{Remote: remoteError}
+	case UnableToSetCookie:
+		return &UnableToSetCookieError{Remote: remoteError}
+	case UnableToCaptureScreen:
+		return &UnableToCaptureScreenError{Remote: remoteError}
+	case UnknownCommand:
+		return &UnknownCommandError{Remote: remoteError}
+	case UnknownError:
+		return &UnknownErrorError{Remote: remoteError}
+	case UnknownMethod:
+		return &UnknownMethodError{Remote: remoteError}
+	case UnsupportedOperation:
+		return &UnsupportedOperationError{Remote: remoteError}
+	}
+
+	return remoteError
+
+}
+
+//ElementClickInterceptedError is returned when the element click was intercepted by another element
+type ElementClickInterceptedError struct {
+	Remote *Error
+}
+
+func (e *ElementClickInterceptedError) Error() string {
+	return e.Remote.Error()
+}
+
+//Unwrap returns the underlying remote error
+func (e *ElementClickInterceptedError) Unwrap() error {
+	return e.Remote
+}
+
+//ElementNotInteractableError is returned when the element cannot be interacted with
+type ElementNotInteractableError struct {
+	Remote *Error
+}
+
+func (e *ElementNotInteractableError) Error() string {
+	return e.Remote.Error()
+}
+
+//Unwrap returns the underlying remote error
+func (e *ElementNotInteractableError) Unwrap() error {
+	return e.Remote
+}
+
+//InsecureCertificateError is returned when navigation hit an insecure certificate
+type InsecureCertificateError struct {
+	Remote *Error
+}
+
+func (e *InsecureCertificateError) Error() string {
+	return e.Remote.Error()
+}
+
+//Unwrap returns the underlying remote error
+func (e *InsecureCertificateError) Unwrap() error {
+	return e.Remote
+}
+
+//InvalidArgumentError is returned when a command argument is invalid
+type InvalidArgumentError struct {
+	Remote *Error
+}
+
+func (e *InvalidArgumentError) Error() string {
+	return e.Remote.Error()
+}
+
+//Unwrap returns the underlying remote error
+func (e *InvalidArgumentError) Unwrap() error {
+	return e.Remote
+}
+
+//InvalidCookieDomainError is returned when a cookie was set for a different domain
+type InvalidCookieDomainError struct {
+	Remote *Error
+}
+
+func (e *InvalidCookieDomainError) Error() string {
+	return e.Remote.Error()
+}
+
+//Unwrap returns the underlying remote error
+func (e *InvalidCookieDomainError) Unwrap() error {
+	return e.Remote
+}
+
+//InvalidElementStateError is returned when the element is in a state that rejects the command
+type InvalidElementStateError struct {
+	Remote *Error
+}
+
+func (e *InvalidElementStateError) Error() string {
+	return e.Remote.Error()
+}
+
+//Unwrap returns the underlying remote error
+func (e *InvalidElementStateError) Unwrap() error {
+	return e.Remote
+}
+
+//InvalidSelectorError is returned when the element selector is invalid
+type InvalidSelectorError struct {
+	Remote *Error
+}
+
+func (e *InvalidSelectorError) Error() string {
+	return e.Remote.Error()
+}
+
+//Unwrap returns the underlying remote error
+func (e *InvalidSelectorError) Unwrap() error {
+	return e.Remote
+}
+
+//InvalidSessionIDError is returned when the session does not exist or was deleted
+type InvalidSessionIDError struct {
+	Remote *Error
+}
+
+func (e *InvalidSessionIDError) Error() string {
+	return e.Remote.Error()
+}
+
+//Unwrap returns the underlying remote error
+func (e *InvalidSessionIDError) Unwrap() error {
+	return e.Remote
+}
+
+//JavascriptErrorError is returned when a script threw an error
+type JavascriptErrorError struct {
+	Remote *Error
+}
+
+func (e *JavascriptErrorError) Error() string {
+	return e.Remote.Error()
+}
+
+//Unwrap returns the underlying remote error
+func (e *JavascriptErrorError) Unwrap() error {
+	return e.Remote
+}
+
+//MoveTargetOutOfBoundsError is returned when a pointer action moved outside of the viewport
+type MoveTargetOutOfBoundsError struct {
+	Remote *Error
+}
+
+func (e *MoveTargetOutOfBoundsError) Error() string {
+	return e.Remote.Error()
+}
+
+//Unwrap returns the underlying remote error
+func (e *MoveTargetOutOfBoundsError) Unwrap() error {
+	return e.Remote
+}
+
+//NoSuchAlertError is returned when no user prompt is open
+type NoSuchAlertError struct {
+	Remote *Error
+}
+
+func (e *NoSuchAlertError) Error() string {
+	return e.Remote.Error()
+}
+
+//Unwrap returns the underlying remote error
+func (e *NoSuchAlertError) Unwrap() error {
+	return e.Remote
+}
+
+//NoSuchCookieError is returned when no cookie matches the requested name
+type NoSuchCookieError struct {
+	Remote *Error
+}
+
+func (e *NoSuchCookieError) Error() string {
+	return e.Remote.Error()
+}
+
+//Unwrap returns the underlying remote error
+func (e *NoSuchCookieError) Unwrap() error {
+	return e.Remote
+}
+
+//NoSuchElementError is returned when no element matches the locator
+type NoSuchElementError struct {
+	Remote *Error
+}
+
+func (e *NoSuchElementError) Error() string {
+	return e.Remote.Error()
+}
+
+//Unwrap returns the underlying remote error
+func (e *NoSuchElementError) Unwrap() error {
+	return e.Remote
+}
+
+//NoSuchFrameError is returned when the frame to switch to does not exist
+type NoSuchFrameError struct {
+	Remote *Error
+}
+
+func (e *NoSuchFrameError) Error() string {
+	return e.Remote.Error()
+}
+
+//Unwrap returns the underlying remote error
+func (e *NoSuchFrameError) Unwrap() error {
+	return e.Remote
+}
+
+//NoSuchWindowError is returned when the window to use does not exist
+type NoSuchWindowError struct {
+	Remote *Error
+}
+
+func (e *NoSuchWindowError) Error() string {
+	return e.Remote.Error()
+}
+
+//Unwrap returns the underlying remote error
+func (e *NoSuchWindowError) Unwrap() error {
+	return e.Remote
+}
+
+//NoSuchShadowRootError is returned when the element has no shadow root
+type NoSuchShadowRootError struct {
+	Remote *Error
+}
+
+func (e *NoSuchShadowRootError) Error() string {
+	return e.Remote.Error()
+}
+
+//Unwrap returns the underlying remote error
+func (e *NoSuchShadowRootError) Unwrap() error {
+	return e.Remote
+}
+
+//ScriptTimeoutError is returned when a script did not finish before the script timeout
+type ScriptTimeoutError struct {
+	Remote *Error
+}
+
+func (e *ScriptTimeoutError) Error() string {
+	return e.Remote.Error()
+}
+
+//Unwrap returns the underlying remote error
+func (e *ScriptTimeoutError) Unwrap() error {
+	return e.Remote
+}
+
+//SessionNotCreatedError is returned when a new session could not be created
+type SessionNotCreatedError struct {
+	Remote *Error
+}
+
+func (e *SessionNotCreatedError) Error() string {
+	return e.Remote.Error()
+}
+
+//Unwrap returns the underlying remote error
+func (e *SessionNotCreatedError) Unwrap() error {
+	return e.Remote
+}
+
+//StaleElementReferenceError is returned when the element is no longer attached to the document
+type StaleElementReferenceError struct {
+	Remote *Error
+}
+
+func (e *StaleElementReferenceError) Error() string {
+	return e.Remote.Error()
+}
+
+//Unwrap returns the underlying remote error
+func (e *StaleElementReferenceError) Unwrap() error {
+	return e.Remote
+}
+
+//DetachedShadowRootError is returned when the shadow root is no longer attached to the document
+type DetachedShadowRootError struct {
+	Remote *Error
+}
+
+func (e *DetachedShadowRootError) Error() string {
+	return e.Remote.Error()
+}
+
+//Unwrap returns the underlying remote error
+func (e *DetachedShadowRootError) Unwrap() error {
+	return e.Remote
+}
+
+//TimeoutError is returned when a command did not finish in time
+type TimeoutError struct {
+	Remote *Error
+}
+
+func (e *TimeoutError) Error() string {
+	return e.Remote.Error()
+}
+
+//Unwrap returns the underlying remote error
+func (e *TimeoutError) Unwrap() error {
+	return e.Remote
+}
+
+//UnableToSetCookieError is returned when a cookie could not be set
+type UnableToSetCookieError struct {
+	Remote *Error
+}
+
+func (e *UnableToSetCookieError) Error() string {
+	return e.Remote.Error()
+}
+
+//Unwrap returns the underlying remote error
+func (e *UnableToSetCookieError) Unwrap() error {
+	return e.Remote
+}
+
+//UnableToCaptureScreenError is returned when a screenshot could not be taken
+type UnableToCaptureScreenError struct {
+	Remote *Error
+}
+
+func (e *UnableToCaptureScreenError) Error() string {
+	return e.Remote.Error()
+}
+
+//Unwrap returns the underlying remote error
+func (e *UnableToCaptureScreenError) Unwrap() error {
+	return e.Remote
+}
+
+//UnknownCommandError is returned when the remote end does not know the command
+type UnknownCommandError struct {
+	Remote *Error
+}
+
+func (e *UnknownCommandError) Error() string {
+	return e.Remote.Error()
+}
+
+//Unwrap returns the underlying remote error
+func (e *UnknownCommandError) Unwrap() error {
+	return e.Remote
+}
+
+//UnknownErrorError is returned when the remote end failed for an unknown reason
+type UnknownErrorError struct {
+	Remote *Error
+}
+
+func (e *UnknownErrorError) Error() string {
+	return e.Remote.Error()
+}
+
+//Unwrap returns the underlying remote error
+func (e *UnknownErrorError) Unwrap() error {
+	return e.Remote
+}
+
+//UnknownMethodError is returned when the command does not support the HTTP method
+type UnknownMethodError struct {
+	Remote *Error
+}
+
+func (e *UnknownMethodError) Error() string {
+	return e.Remote.Error()
+}
+
+//Unwrap returns the underlying remote error
+func (e *UnknownMethodError) Unwrap() error {
+	return e.Remote
+}
+
+//UnsupportedOperationError is returned when the remote end does not support the operation
+type UnsupportedOperationError struct {
+	Remote *Error
+}
+
+func (e *UnsupportedOperationError) Error() string {
+	return e.Remote.Error()
+}
+
+//Unwrap returns the underlying remote error
+func (e *UnsupportedOperationError) Unwrap() error {
+	return e.Remote
 }
@@ -0,0 +1,63 @@
+package selenium
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplyGetError(t *testing.T) {
+
+	reply := &Reply{
+		StatusCode: 404,
+		Method:     POST,
+		Endpoint:   "http://127.0.0.1:4444/session/1/element",
+		Data: map[string]interface{}{
+			"value": map[string]interface{}{
+				"error":      "no such element",
+				"message":    "Unable to locate element",
+				"stacktrace": "trace",
+			},
+		},
+	}
+
+	err := reply.GetError()
+	require.Truef(t, errors.Is(err, NoSuchElement), "Error should match its error code.")
+	require.Falsef(t, errors.Is(err, StaleElementReference), "Error should not match other error codes.")
+
+	var noSuchElementError *NoSuchElementError
+	require.Truef(t, errors.As(err, &noSuchElementError), "Error should be a no such element error.")
+	var staleElementError *StaleElementReferenceError
+	require.Falsef(t, errors.As(err, &staleElementError), "Error should not be a stale element reference error.")
+
+	var remoteError *Error
+	require.Truef(t, errors.As(err, &remoteError), "Error should be a remote error.")
+	require.Equalf(t, "Unable to locate element", remoteError.Message, "Remote error should carry the message.")
+	require.Equalf(t, "trace", remoteError.Stacktrace, "Remote error should carry the stacktrace.")
+	require.Equalf(t, 404, remoteError.StatusCode, "Remote error should carry the HTTP status.")
+	require.Equalf(t, POST, remoteError.Method, "Remote error should carry the failed command.")
+
+}
+
+func TestReplyGetErrorUnexpectedAlertOpen(t *testing.T) {
+
+	reply := &Reply{
+		StatusCode: 500,
+		Data: map[string]interface{}{
+			"value": map[string]interface{}{
+				"error":   "unexpected alert open",
+				"message": "unexpected alert open",
+				"data":    map[string]interface{}{"text": "Are you sure?"},
+			},
+		},
+	}
+
+	err := reply.GetError()
+	require.Truef(t, errors.Is(err, UnexpectedAlertOpen), "Error should match its error code.")
+
+	var alertError *UnexpectedAlertOpenError
+	require.Truef(t, errors.As(err, &alertError), "Error should be an unexpected alert open error.")
+	require.Equalf(t, "Are you sure?", alertError.Text, "Error should carry the alert text.")
+
+}
@@ -0,0 +1,126 @@
+package selenium
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+//CommandExecutor sends WebDriver commands to a remote end. The endpoint is the command path relative to the remote end URL.
+type CommandExecutor interface {
+	Execute(ctx context.Context, method Method, endpoint string, data interface{}) (*Reply, error)
+}
+
+type httpCommandExecutor struct {
+	url      string
+	client   *http.Client
+	header   http.Header
+	username string
+	password string
+}
+
+//NewHTTPCommandExecutor returns a CommandExecutor sending commands over HTTP to the remote end at url
+func NewHTTPCommandExecutor(url string) *httpCommandExecutor {
+	return &httpCommandExecutor{url: url, client: http.DefaultClient, header: make(http.Header)}
+}
+
+//GetURL returns the URL of the remote end
+func (executor *httpCommandExecutor) GetURL() string {
+	return executor.url
+}
+
+//SetClient sets the HTTP client used to send commands
+func (executor *httpCommandExecutor) SetClient(client *http.Client) {
+	executor.client = client
+}
+
+//SetTLSConfig sets the TLS configuration used to connect to an HTTPS remote end
+func (executor *httpCommandExecutor) SetTLSConfig(config *tls.Config) {
+
+	transport, ok := executor.client.Transport.(*http.Transport)
+	if !ok || transport == nil {
+		transport = http.DefaultTransport.(*http.Transport)
+	}
+
+	transport = transport.Clone()
+	transport.TLSClientConfig = config
+
+	client := *executor.client
+	client.Transport = transport
+	executor.client = &client
+
+}
+
+//SetHeader sets a header sent with every command
+func (executor *httpCommandExecutor) SetHeader(name string, value string) {
+	executor.header.Set(name, value)
+}
+
+//SetBasicAuth sets the credentials sent with every command, as required by most remote grids
+func (executor *httpCommandExecutor) SetBasicAuth(username string, password string) {
+	executor.username = username
+	executor.password = password
+}
+
+//Execute sends a command to the remote end, aborting the request when ctx is done
+func (executor *httpCommandExecutor) Execute(ctx context.Context, method Method, endpoint string, data interface{}) (*Reply, error) {
+
+	var body io.Reader
+
+	if method == POST {
+
+		if data != nil {
+			jsonData, err := json.Marshal(data)
+			if err != nil {
+				return nil, err
+			}
+			body = bytes.NewBuffer(jsonData)
+		}
+
+	} else if method != GET && method != DELETE {
+		return nil, errors.New("unsupported http method: " + string(method))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, string(method), executor.url+endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, values := range executor.header {
+		req.Header[name] = values
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	}
+
+	if executor.username != "" || executor.password != "" {
+		req.SetBasicAuth(executor.username, executor.password)
+	}
+
+	resp, err := executor.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	replyData := make(map[string]interface{}, 0)
+	err = json.Unmarshal(respBody, &replyData)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Reply{StatusCode: resp.StatusCode, Data: replyData, Method: method, Endpoint: endpoint}, nil
+
+}
@@ -0,0 +1,58 @@
+package selenium
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type recordingExecutor struct {
+	method   Method
+	endpoint string
+	reply    *Reply
+}
+
+func (executor *recordingExecutor) Execute(ctx context.Context, method Method, endpoint string, data interface{}) (*Reply, error) {
+	executor.method = method
+	executor.endpoint = endpoint
+	return executor.reply, nil
+}
+
+func TestRemoteWithExecutor(t *testing.T) {
+
+	executor := &recordingExecutor{reply: &Reply{StatusCode: 200, Data: map[string]interface{}{"value": "<html></html>"}}}
+
+	wd := NewRemoteWithExecutor(executor, NewCapabilities())
+	wd.SetSession("1", nil)
+
+	source, err := wd.GetPageSource()
+	require.NoErrorf(t, err, "Getting the page source should not raise any errors.")
+	require.Equalf(t, "<html></html>", source, "Page source should be read from the executor reply.")
+	require.Equalf(t, GET, executor.method, "Command should be sent through the executor.")
+	require.Equalf(t, "/session/1/source", executor.endpoint, "Command should be sent through the executor.")
+
+}
+
+func TestHTTPCommandExecutor(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, _ := r.BasicAuth()
+		w.Write([]byte(`{"value": {"path": "` + r.URL.Path + `", "user": "` + username + `:` + password + `", "header": "` + r.Header.Get("X-Grid") + `"}}`))
+	}))
+	defer server.Close()
+
+	executor := NewHTTPCommandExecutor(server.URL + "/wd/hub")
+	executor.SetBasicAuth("user", "secret")
+	executor.SetHeader("X-Grid", "ci")
+
+	reply, err := executor.Execute(context.Background(), GET, "/status", nil)
+	require.NoErrorf(t, err, "Executing a command should not raise any errors.")
+
+	value, err := reply.GetMap("value", false)
+	require.NoErrorf(t, err, "Reply should contain a value.")
+	require.Equalf(t, map[string]interface{}{"path": "/wd/hub/status", "user": "user:secret", "header": "ci"}, value, "Request should use the configured URL, credentials and headers.")
+
+}
@@ -0,0 +1,31 @@
+package firefox
+
+import (
+	"errors"
+
+	"../../selenium"
+)
+
+func init() {
+	selenium.RegisterBrowser("firefox", selenium.Browser{
+		NewOptions: func() selenium.Options { return new(Options) },
+		Start:      start,
+	})
+}
+
+//start starts geckodriver, found from the GECKODRIVER environment variable or $PATH, on a free port
+func start(options selenium.Options, middlewares ...selenium.Middleware) (selenium.Driver, error) {
+
+	firefoxOptions, ok := options.(*Options)
+	if !ok {
+		return nil, errors.New("firefox requires *firefox.Options")
+	}
+
+	driver, err := Driver("", 0, firefoxOptions, middlewares...)
+	if err != nil {
+		return nil, err
+	}
+
+	return driver, nil
+
+}
@@ -6,3 +6,15 @@ type Capabilities struct {
 	selenium.Capabilities
 	FirefoxOptions *Options `json:"moz:firefoxOptions,omitempty"`
 }
+
+//MarshalJSON encodes the W3C capabilities and the firefox options as a single object
+func (caps *Capabilities) MarshalJSON() ([]byte, error) {
+
+	extensions := make(map[string]interface{})
+	if caps.FirefoxOptions != nil {
+		extensions["moz:firefoxOptions"] = caps.FirefoxOptions
+	}
+
+	return selenium.MarshalCapabilities(caps.Capabilities, extensions)
+
+}
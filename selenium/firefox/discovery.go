@@ -0,0 +1,11 @@
+package firefox
+
+import "../../selenium"
+
+//DriverEnv is the environment variable naming the geckodriver binary
+const DriverEnv = "GECKODRIVER"
+
+//FindDriver returns the path of geckodriver from the explicit path, the GECKODRIVER environment variable or $PATH
+func FindDriver(path string) (string, error) {
+	return selenium.FindBinary(path, DriverEnv, "geckodriver")
+}
@@ -1,20 +1,19 @@
 package firefox
 
 import (
-	"fmt"
-	"os/exec"
 	"strconv"
-	"time"
 
 	"../../selenium"
 )
 
 type Options struct {
-	Args    []string               `json:",omitempty"`
-	Binary  string                 `json:",omitempty"`
-	Profile string                 `json:",omitempty"`
-	Log     Log                    `json:",omitempty"`
-	Prefs   map[string]interface{} `json:",omitempty"`
+	Args    []string               `json:"args,omitempty"`
+	Binary  string                 `json:"binary,omitempty"`
+	Profile string                 `json:"profile,omitempty"`
+	Log     Log                    `json:"log,omitempty"`
+	Prefs   map[string]interface{} `json:"prefs,omitempty"`
+
+	selenium.BrowserOptions
 }
 
 func (options *Options) AddArgs(args ...string) {
@@ -22,96 +21,70 @@ func (options *Options) AddArgs(args ...string) {
 }
 
 func (options *Options) AddPref(name string, value interface{}) {
+	if options.Prefs == nil {
+		options.Prefs = make(map[string]interface{})
+	}
 	options.Prefs[name] = value
 }
 
+//SetBinary sets the path of the Firefox binary
+func (options *Options) SetBinary(binary string) {
+	options.Binary = binary
+}
+
+//SetHeadless runs Firefox without a visible window
+func (options *Options) SetHeadless(headless bool) {
+	options.Args = selenium.RemoveArg(options.Args, "-headless", 0)
+	if headless {
+		options.AddArgs("-headless")
+	}
+}
+
+//SetWindowSize sets the initial size of the browser window
+func (options *Options) SetWindowSize(width int, height int) {
+	options.Args = selenium.RemoveArg(options.Args, "-width", 1)
+	options.Args = selenium.RemoveArg(options.Args, "-height", 1)
+	options.AddArgs("-width", strconv.Itoa(width), "-height", strconv.Itoa(height))
+}
+
 //Log sets the logging verbosity of geckodriver and Firefox, including all specified level logs and above.
 type Log struct {
 	Level string `json:"level,omitempty"`
 }
 
 type geckoDriver struct {
-	selenium.WebDriver
-	cmd *exec.Cmd
+	*selenium.LocalDriver
 }
 
-//Driver starts the geckodriver server on the specified port and returns a WebDriver implementation (or error)
-func Driver(path string, port int, options *Options) (*geckoDriver, error) {
+//Driver starts the geckodriver server on the specified port, or a free port if 0, and returns a WebDriver implementation (or error).
+//When path is empty geckodriver is looked up from the GECKODRIVER environment variable and $PATH.
+//Every command, including the new session, is sent through middlewares.
+func Driver(path string, port int, options *Options, middlewares ...selenium.Middleware) (*geckoDriver, error) {
 
-	cmd := exec.Command(path, "--port="+strconv.Itoa(port))
-	err := startGeckoDriver(cmd)
+	path, err := FindDriver(path)
 	if err != nil {
 		return nil, err
 	}
 
-	url := fmt.Sprintf("http://127.0.0.1:%d", port)
-
-	caps := &Capabilities{selenium.NewCapabilities(), options}
-	caps.SetBrowserName("firefox")
-
-	driver := selenium.NewRemote(url, caps)
-	if err != nil {
-		return nil, err
-	}
-
-	for retries := 3; retries > 0; retries-- {
-
-		status, err := driver.GetStatus()
-		if err != nil {
-			return nil, err
-		}
-
-		if status.IsReady() {
-			break
-		}
-
-		time.Sleep(5 * time.Second)
-
-	}
-
-	_, err = driver.NewSession()
-	if err != nil {
-		return nil, err
-	}
-
-	return &geckoDriver{driver, cmd}, nil
+	return DriverWithService(selenium.NewService(path, port), options, middlewares...)
 
 }
 
-func startGeckoDriver(cmd *exec.Cmd) error {
-
-	/*
+//DriverWithService starts service, e.g. configured to capture the geckodriver logs, and returns a WebDriver implementation (or error)
+func DriverWithService(service *selenium.Service, options *Options, middlewares ...selenium.Middleware) (*geckoDriver, error) {
 
-		stdout, err := cmd.StdoutPipe()
-		if err != nil {
-			return err
-		}
-
-		stderr, err := cmd.StderrPipe()
-		if err != nil {
-			return err
-		}
-
-	*/
+	caps := &Capabilities{selenium.NewCapabilities(), options}
+	caps.SetBrowserName("firefox")
 
-	if err := cmd.Start(); err != nil {
-		return err
+	if options != nil {
+		options.Apply(caps)
 	}
 
-	//go func() { selenium.Log(stdout) }()
-	//go func() { selenium.Log(stderr) }()
-
-	return nil
-}
-
-//Quit calls WebDriver "Delete Session" command and kills the geckodriver process
-func (driver *geckoDriver) Quit() error {
-
-	err := driver.DeleteSession()
+	driver, err := selenium.StartLocalDriver(service, caps, middlewares...)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return driver.cmd.Process.Kill()
+	return &geckoDriver{driver}, nil
 
 }
@@ -13,7 +13,7 @@ import (
 
 func TestFirefox(t *testing.T) {
 
-	driver, err := Driver(GECKODRIVER_PATH, 4444, nil)
+	driver, err := Driver("", 0, nil)
 	require.NoErrorf(t, err, "Creation of GeckoDriver should not raise any errors.")
 
 	timeouts, err := driver.GetTimeouts()
@@ -0,0 +1,93 @@
+package selenium
+
+import "context"
+
+//LocalDriver is the driver of a session running on a driver server started by a Service.
+//Browser packages embed it, adding their own commands. It implements WebDriverInfo by forwarding to the driver it wraps,
+//which must implement it as the drivers returned by NewRemote do, so that packages such as bidi can reach the session.
+type LocalDriver struct {
+	WebDriver
+	service *Service
+}
+
+//StartLocalDriver starts service and a session with caps, sending every command, including the new session, through middlewares.
+//The service is stopped when it does not become ready or the session cannot be created, so no process is left behind.
+func StartLocalDriver(service *Service, caps Capabilities, middlewares ...Middleware) (*LocalDriver, error) {
+
+	err := service.Start()
+	if err != nil {
+		service.Stop()
+		return nil, err
+	}
+
+	wd := NewRemote(service.GetURL(), caps)
+	wd.Use(middlewares...)
+
+	_, err = wd.NewSession()
+	if err != nil {
+		service.Stop()
+		return nil, err
+	}
+
+	return &LocalDriver{wd, service}, nil
+
+}
+
+//GetService returns the service running the driver server
+func (driver *LocalDriver) GetService() *Service {
+	return driver.service
+}
+
+//CopyWithContext returns a copy of the driver whose commands are aborted when ctx is done, for browser drivers to wrap
+func (driver *LocalDriver) CopyWithContext(ctx context.Context) *LocalDriver {
+	return &LocalDriver{driver.WebDriver.WithContext(ctx), driver.service}
+}
+
+//WithContext returns a copy of the driver whose commands are aborted when ctx is done
+func (driver *LocalDriver) WithContext(ctx context.Context) WebDriver {
+	return driver.CopyWithContext(ctx)
+}
+
+//GetURL returns the URL of the driver server
+func (driver *LocalDriver) GetURL() string {
+	return driver.info().GetURL()
+}
+
+//GetExecutor returns the executor sending the commands of the session
+func (driver *LocalDriver) GetExecutor() CommandExecutor {
+	return driver.info().GetExecutor()
+}
+
+//GetDesiredCapabilities returns the capabilities requested for the session
+func (driver *LocalDriver) GetDesiredCapabilities() Capabilities {
+	return driver.info().GetDesiredCapabilities()
+}
+
+//GetSession returns the session, with the capabilities returned by the driver server
+func (driver *LocalDriver) GetSession() SessionInfo {
+	return driver.info().GetSession()
+}
+
+//GetContext returns the context of the commands, set with WithContext
+func (driver *LocalDriver) GetContext() context.Context {
+	return driver.info().GetContext()
+}
+
+func (driver *LocalDriver) info() WebDriverInfo {
+	return driver.WebDriver.(WebDriverInfo)
+}
+
+//Quit calls WebDriver "Delete Session" command and stops the driver server with the browsers it started
+func (driver *LocalDriver) Quit() error {
+
+	//the server is stopped even if the session could not be deleted, so no process is left behind
+	err := driver.DeleteSession()
+
+	stopErr := driver.service.Stop()
+	if err != nil {
+		return err
+	}
+
+	return stopErr
+
+}
@@ -0,0 +1,105 @@
+package selenium
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+//CommandExecutorFunc is an adapter to use an ordinary function as a CommandExecutor
+type CommandExecutorFunc func(ctx context.Context, method Method, endpoint string, data interface{}) (*Reply, error)
+
+//Execute calls f(ctx, method, endpoint, data)
+func (f CommandExecutorFunc) Execute(ctx context.Context, method Method, endpoint string, data interface{}) (*Reply, error) {
+	return f(ctx, method, endpoint, data)
+}
+
+//Middleware intercepts every command sent through a CommandExecutor. It receives the next executor of the chain.
+type Middleware func(next CommandExecutor) CommandExecutor
+
+//Chain wraps executor with middlewares. The first middleware is the outermost one and sees each command first.
+func Chain(executor CommandExecutor, middlewares ...Middleware) CommandExecutor {
+
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		executor = middlewares[i](executor)
+	}
+
+	return executor
+
+}
+
+//LoggingMiddleware logs every command with its status and duration. Payloads and replies are logged at debug level,
+//except for the commands carrying typed text or cookie values, which are redacted.
+func LoggingMiddleware(logger *slog.Logger) Middleware {
+
+	return func(next CommandExecutor) CommandExecutor {
+		return CommandExecutorFunc(func(ctx context.Context, method Method, endpoint string, data interface{}) (*Reply, error) {
+
+			logger.DebugContext(ctx, "webdriver command", "method", method, "endpoint", endpoint, "payload", redact(endpoint, data))
+
+			start := time.Now()
+			reply, err := next.Execute(ctx, method, endpoint, data)
+			duration := time.Since(start)
+
+			if err != nil {
+				logger.ErrorContext(ctx, "webdriver command failed", "method", method, "endpoint", endpoint, "duration", duration, "error", err)
+				return reply, err
+			}
+
+			level := slog.LevelInfo
+			if reply.StatusCode != 200 {
+				level = slog.LevelWarn
+			}
+
+			logger.Log(ctx, level, "webdriver reply", "method", method, "endpoint", endpoint, "status", reply.StatusCode, "duration", duration)
+			logger.DebugContext(ctx, "webdriver reply data", "method", method, "endpoint", endpoint, "data", redact(endpoint, reply.Data))
+
+			return reply, err
+
+		})
+	}
+
+}
+
+//redactedCommands are the routes whose payloads or replies may hold secrets, such as passwords typed into an element
+var redactedCommands = []string{"/element/{elementId}/value", "/alert/text", "/actions", "/cookie"}
+
+func redact(endpoint string, data interface{}) interface{} {
+
+	name := CommandName(endpoint)
+
+	for _, command := range redactedCommands {
+		if strings.Contains(name, command) {
+			return "[redacted]"
+		}
+	}
+
+	return data
+
+}
+
+//CommandName returns the route of endpoint with session, element and other identifiers replaced by placeholders,
+//e.g. "/session/{sessionId}/element/{elementId}/click"
+func CommandName(endpoint string) string {
+
+	segments := strings.Split(endpoint, "/")
+
+	for i := 1; i < len(segments); i++ {
+		switch segments[i-1] {
+		case "session":
+			segments[i] = "{sessionId}"
+		case "element":
+			if segments[i] != "active" {
+				segments[i] = "{elementId}"
+			}
+		case "shadow":
+			segments[i] = "{shadowId}"
+		case "attribute", "property", "css", "cookie":
+			segments[i] = "{name}"
+		}
+	}
+
+	return strings.Join(segments, "/")
+
+}
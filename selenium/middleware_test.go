@@ -0,0 +1,73 @@
+package selenium
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddleware(t *testing.T) {
+
+	executor := &recordingExecutor{reply: &Reply{StatusCode: 200, Data: map[string]interface{}{"value": nil}}}
+
+	order := make([]string, 0)
+	trace := func(name string) Middleware {
+		return func(next CommandExecutor) CommandExecutor {
+			return CommandExecutorFunc(func(ctx context.Context, method Method, endpoint string, data interface{}) (*Reply, error) {
+				order = append(order, name)
+				return next.Execute(ctx, method, endpoint, data)
+			})
+		}
+	}
+
+	buffer := new(bytes.Buffer)
+	stats := NewLatencyStats()
+
+	wd := NewRemoteWithExecutor(executor, NewCapabilities())
+	wd.SetSession("1", nil)
+	wd.Use(trace("outer"), trace("inner"), stats.Middleware(), LoggingMiddleware(slog.New(slog.NewTextHandler(buffer, nil))))
+
+	require.NoErrorf(t, wd.ElementClick(&webElement{id: WebElementIdentifier, value: "a"}), "Click should not raise any errors.")
+	require.NoErrorf(t, wd.ElementClick(&webElement{id: WebElementIdentifier, value: "b"}), "Click should not raise any errors.")
+
+	require.Equalf(t, []string{"outer", "inner", "outer", "inner"}, order, "Middlewares should run in the order they were added.")
+	require.Truef(t, strings.Contains(buffer.String(), "endpoint=/session/1/element/b/click"), "Commands should be logged.")
+
+	snapshot := stats.Snapshot()
+	require.Lenf(t, snapshot, 1, "Clicks on different elements should share one route.")
+	require.Equalf(t, "/session/{sessionId}/element/{elementId}/click", snapshot[0].Command, "Route should not contain identifiers.")
+	require.Equalf(t, 2, snapshot[0].Count, "Every command should be counted.")
+
+}
+
+func TestLoggingMiddlewareRedaction(t *testing.T) {
+
+	cookie := map[string]interface{}{"name": "session", "value": "secret cookie"}
+	executor := &recordingExecutor{reply: &Reply{StatusCode: 200, Data: map[string]interface{}{"value": cookie}}}
+
+	buffer := new(bytes.Buffer)
+	logger := slog.New(slog.NewTextHandler(buffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	wd := NewRemoteWithExecutor(executor, NewCapabilities())
+	wd.SetSession("1", nil)
+	wd.Use(LoggingMiddleware(logger))
+
+	require.NoErrorf(t, wd.ElementSendKeys(&webElement{id: WebElementIdentifier, value: "a"}, "password"), "Send keys should not raise any errors.")
+	_, err := wd.GetNamedCookie("session")
+	require.NoErrorf(t, err, "Get cookie should not raise any errors.")
+
+	require.Falsef(t, strings.Contains(buffer.String(), "password"), "Typed text should not be logged.")
+	require.Falsef(t, strings.Contains(buffer.String(), "secret cookie"), "Cookie values should not be logged.")
+	require.Truef(t, strings.Contains(buffer.String(), "[redacted]"), "Redacted data should be marked.")
+
+	buffer.Reset()
+	executor.reply = &Reply{StatusCode: 200, Data: map[string]interface{}{"value": "Example Domain"}}
+	_, err = wd.GetTitle()
+	require.NoErrorf(t, err, "Get title should not raise any errors.")
+	require.Truef(t, strings.Contains(buffer.String(), "Example Domain"), "Other replies should be logged.")
+
+}
@@ -0,0 +1,40 @@
+package selenium
+
+//PrintOptions defines the parameters of the "Print Page" command. Zero values are omitted so the remote end defaults apply.
+type PrintOptions struct {
+	Orientation Orientation `json:"orientation,omitempty"`
+	Scale       float64     `json:"scale,omitempty"`
+	Background  bool        `json:"background,omitempty"`
+	Page        *PageSize   `json:"page,omitempty"`
+	Margin      *PageMargin `json:"margin,omitempty"`
+	PageRanges  []string    `json:"pageRanges,omitempty"`
+	ShrinkToFit *bool       `json:"shrinkToFit,omitempty"`
+}
+
+//SetShrinkToFit determines if the content is resized to fit the page width
+func (options *PrintOptions) SetShrinkToFit(shrink bool) {
+	options.ShrinkToFit = &shrink
+}
+
+//Orientation indicates the page orientation used when printing
+type Orientation string
+
+//Constants for Orientation
+const (
+	Portrait  Orientation = "portrait"
+	Landscape Orientation = "landscape"
+)
+
+//PageSize is the printed page size in centimeters
+type PageSize struct {
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+//PageMargin is the printed page margins in centimeters
+type PageMargin struct {
+	Top    float64 `json:"top"`
+	Bottom float64 `json:"bottom"`
+	Left   float64 `json:"left"`
+	Right  float64 `json:"right"`
+}
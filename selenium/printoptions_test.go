@@ -0,0 +1,35 @@
+package selenium
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintPage(t *testing.T) {
+
+	server, wd := newCommandServer(t)
+	defer server.Close()
+
+	server.value = base64.StdEncoding.EncodeToString([]byte("%PDF-1.4"))
+
+	options := &PrintOptions{Orientation: Landscape, Page: &PageSize{Width: 21, Height: 29.7}, PageRanges: []string{"1-2"}}
+	options.SetShrinkToFit(false)
+
+	pdf, err := wd.PrintPage(options)
+	require.NoErrorf(t, err, "Page should be printed.")
+	require.Equalf(t, "%PDF-1.4", string(pdf), "PDF should be decoded from base64.")
+	require.Equalf(t, "POST /session/1/print", server.command, "Print should be posted.")
+	require.Equalf(t, map[string]interface{}{
+		"orientation": "landscape",
+		"page":        map[string]interface{}{"width": 21.0, "height": 29.7},
+		"pageRanges":  []interface{}{"1-2"},
+		"shrinkToFit": false,
+	}, server.payload, "Only the options set should be sent.")
+
+	_, err = wd.PrintPage(nil)
+	require.NoErrorf(t, err, "Page should be printed with the default options.")
+	require.Equalf(t, map[string]interface{}{}, server.payload, "Default options should be an empty object.")
+
+}
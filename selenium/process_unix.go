@@ -0,0 +1,37 @@
+//go:build !windows
+
+package selenium
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+var interruptSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+//isExecutable reports whether any of the execute permission bits of the file is set
+func isExecutable(info os.FileInfo) bool {
+	return info.Mode()&0111 != 0
+}
+
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+//terminateProcessGroup sends SIGTERM to the process group led by cmd
+func terminateProcessGroup(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGTERM)
+}
+
+//killProcessGroup sends SIGKILL to the process group led by cmd
+func killProcessGroup(cmd *exec.Cmd) error {
+
+	err := syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	if err != nil {
+		return cmd.Process.Kill()
+	}
+
+	return nil
+
+}
@@ -0,0 +1,27 @@
+//go:build windows
+
+package selenium
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+)
+
+var interruptSignals = []os.Signal{os.Interrupt}
+
+//isExecutable is always true on Windows, which has no execute permission bits
+func isExecutable(info os.FileInfo) bool {
+	return true
+}
+
+func setProcessGroup(cmd *exec.Cmd) {}
+
+//terminateProcessGroup is not supported on Windows, where processes can only be killed
+func terminateProcessGroup(cmd *exec.Cmd) error {
+	return errors.New("graceful termination is not supported on windows")
+}
+
+func killProcessGroup(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
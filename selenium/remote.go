@@ -1,8 +1,11 @@
 package selenium
 
 import (
+	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"net/url"
 
 	"./by"
 )
@@ -11,24 +14,85 @@ type remoteWebDriver struct {
 	session             *session
 	desiredCapabilities Capabilities `json:"capabilities,omitempty"`
 	url                 string
+	executor            CommandExecutor
+	ctx                 context.Context
 }
 
 //NewRemote returns a pointer to an implementation of the W3C WebDriver client protocol
 func NewRemote(url string, desiredCapabilities Capabilities) *remoteWebDriver {
 
-	return &remoteWebDriver{desiredCapabilities: desiredCapabilities, url: url}
+	return NewRemoteWithExecutor(NewHTTPCommandExecutor(url), desiredCapabilities)
 
 }
 
+//NewRemoteWithExecutor returns a pointer to an implementation of the W3C WebDriver client protocol sending its commands through executor
+func NewRemoteWithExecutor(executor CommandExecutor, desiredCapabilities Capabilities) *remoteWebDriver {
+
+	wd := &remoteWebDriver{desiredCapabilities: desiredCapabilities, executor: executor, ctx: context.Background()}
+
+	if located, ok := executor.(interface{ GetURL() string }); ok {
+		wd.url = located.GetURL()
+	}
+
+	return wd
+
+}
+
+//WithContext returns a copy of the driver, sharing its current session, whose commands are aborted when ctx is done.
+//Elements and shadow roots found through the copy stay bound to it, so their own methods fail once ctx is done;
+//pass them to the commands of another driver, e.g. driver.ElementClick(element), or rebind them with SetDriver.
+func (wd *remoteWebDriver) WithContext(ctx context.Context) WebDriver {
+
+	driver := *wd
+	driver.ctx = ctx
+
+	return &driver
+
+}
+
+func (wd *remoteWebDriver) GetContext() context.Context {
+	return wd.ctx
+}
+
+//Use wraps the driver's command executor with middlewares, e.g. for logging, tracing or metrics
+func (wd *remoteWebDriver) Use(middlewares ...Middleware) {
+	wd.executor = Chain(wd.executor, middlewares...)
+}
+
 func (wd *remoteWebDriver) SetSession(id string, caps map[string]interface{}) {
 
-	wd.session = &session{id, caps}
+	wd.session = &session{ID: id, Capabilities: caps, Dialect: W3CDialect}
+}
+
+//SetDialect sets the wire protocol spoken by the remote end of the current session
+func (wd *remoteWebDriver) SetDialect(dialect Dialect) {
+
+	if wd.session != nil {
+		wd.session.Dialect = dialect
+	}
+
+}
+
+//execute sends a command through the executor, translating it to the dialect negotiated for the session
+func (wd *remoteWebDriver) execute(ctx context.Context, method Method, endpoint string, data interface{}) (*Reply, error) {
+
+	dialect := W3CDialect
+	if wd.session != nil {
+		dialect = wd.session.Dialect
+	}
+
+	return executeDialect(ctx, wd.executor, dialect, method, endpoint, data)
+
 }
 
 func (wd *remoteWebDriver) GetURL() string {
 	return wd.url
 }
 
+func (wd *remoteWebDriver) GetExecutor() CommandExecutor {
+	return wd.executor
+}
+
 func (wd *remoteWebDriver) GetDesiredCapabilities() Capabilities {
 	return wd.desiredCapabilities
 }
@@ -38,15 +102,19 @@ func (wd *remoteWebDriver) GetSession() SessionInfo {
 }
 
 //NewSession creates a single instantiation of a particular user agent and returns the session ID.
+//Capabilities are sent in both the W3C and the legacy form, and the dialect of the remote end is negotiated from its reply.
 func (wd *remoteWebDriver) NewSession() (SessionInfo, error) {
 
-	//chrome map[string]interface{}{"desiredCapabilities": wd.Capabilities},
-
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.executor.Execute(
+		wd.ctx,
 		POST,
-		wd.url+"/session",
+		"/session",
 		map[string]interface{}{
-			"alwaysMatch": wd.desiredCapabilities,
+			"capabilities": map[string]interface{}{
+				"alwaysMatch": wd.desiredCapabilities,
+				"firstMatch":  []interface{}{map[string]interface{}{}},
+			},
+			"desiredCapabilities": wd.desiredCapabilities,
 		},
 	)
 
@@ -54,18 +122,34 @@ func (wd *remoteWebDriver) NewSession() (SessionInfo, error) {
 		return nil, err
 	}
 
-	fmt.Println(reply.Data)
+	dialect := negotiateDialect(reply)
 
-	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return nil, errors.New(message)
+	if dialect == LegacyDialect {
+
+		legacy := legacyReply(reply)
+		if legacy.StatusCode != 200 {
+			return nil, legacy.GetError()
+		}
+
+		id, err := reply.GetString("sessionId", false)
+		if err != nil {
+			return nil, err
 		}
-		return nil, errors.New("non 200 status code received")
+
+		capabilities, err := reply.GetMap("value", false)
+		if err != nil {
+			return nil, err
+		}
+
+		wd.session = &session{ID: id, Capabilities: capabilities, Dialect: dialect}
+
+		return wd.session, nil
+
 	}
 
-	fmt.Println("Data: ")
-	fmt.Println(reply.Data)
+	if reply.StatusCode != 200 {
+		return nil, reply.GetError()
+	}
 
 	id, err := reply.GetString("value.sessionId", true)
 	if err != nil {
@@ -77,10 +161,7 @@ func (wd *remoteWebDriver) NewSession() (SessionInfo, error) {
 		return nil, err
 	}
 
-	fmt.Println("Capabilities: ")
-	fmt.Println(capabilities)
-
-	wd.session = &session{ID: id, Capabilities: capabilities}
+	wd.session = &session{ID: id, Capabilities: capabilities, Dialect: dialect}
 
 	return wd.session, nil
 
@@ -88,9 +169,10 @@ func (wd *remoteWebDriver) NewSession() (SessionInfo, error) {
 
 func (wd *remoteWebDriver) GetStatus() (*Status, error) {
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		GET,
-		fmt.Sprintf("%s/status", wd.url),
+		"/status",
 		nil,
 	)
 
@@ -99,11 +181,13 @@ func (wd *remoteWebDriver) GetStatus() (*Status, error) {
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return nil, errors.New(message)
-		}
-		return nil, errors.New("non 200 status code received")
+		return nil, reply.GetError()
+	}
+
+	//legacy remote ends report readiness with a zero status
+	if status, ok := reply.Data["status"].(float64); ok {
+		message, _ := reply.GetString("value.message", true)
+		return &Status{Ready: status == 0, Message: message}, nil
 	}
 
 	ready, err := reply.GetBool("value.ready", true)
@@ -122,9 +206,10 @@ func (wd *remoteWebDriver) GetStatus() (*Status, error) {
 
 func (wd *remoteWebDriver) GetTimeouts() (*Timeouts, error) {
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		GET,
-		fmt.Sprintf("%s/session/%s/timeouts", wd.url, wd.session.GetID()),
+		fmt.Sprintf("/session/%s/timeouts", wd.session.GetID()),
 		nil,
 	)
 
@@ -133,11 +218,7 @@ func (wd *remoteWebDriver) GetTimeouts() (*Timeouts, error) {
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return nil, errors.New(message)
-		}
-		return nil, errors.New("non 200 status code received")
+		return nil, reply.GetError()
 	}
 
 	script, err := reply.GetFloat("value.script", true)
@@ -161,9 +242,10 @@ func (wd *remoteWebDriver) GetTimeouts() (*Timeouts, error) {
 
 func (wd *remoteWebDriver) SetTimeouts(timeouts *Timeouts) error {
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		POST,
-		fmt.Sprintf("%s/session/%s/timeouts", wd.url, wd.session.GetID()),
+		fmt.Sprintf("/session/%s/timeouts", wd.session.GetID()),
 		timeouts,
 	)
 
@@ -172,11 +254,7 @@ func (wd *remoteWebDriver) SetTimeouts(timeouts *Timeouts) error {
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return errors.New(message)
-		}
-		return errors.New("non 200 status code")
+		return reply.GetError()
 	}
 
 	return nil
@@ -186,9 +264,10 @@ func (wd *remoteWebDriver) SetTimeouts(timeouts *Timeouts) error {
 //DeleteSession closes the current WebDriver session
 func (wd *remoteWebDriver) DeleteSession() error {
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		DELETE,
-		fmt.Sprintf("%s/session/%s", wd.url, wd.session.GetID()),
+		fmt.Sprintf("/session/%s", wd.session.GetID()),
 		nil,
 	)
 
@@ -197,11 +276,7 @@ func (wd *remoteWebDriver) DeleteSession() error {
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return errors.New(message)
-		}
-		return errors.New("non 200 status code")
+		return reply.GetError()
 	}
 
 	return nil
@@ -210,9 +285,10 @@ func (wd *remoteWebDriver) DeleteSession() error {
 
 func (wd *remoteWebDriver) Navigate(url string) error {
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		POST,
-		fmt.Sprintf("%s/session/%s/url", wd.url, wd.session.GetID()),
+		fmt.Sprintf("/session/%s/url", wd.session.GetID()),
 		map[string]interface{}{"url": url},
 	)
 
@@ -221,11 +297,7 @@ func (wd *remoteWebDriver) Navigate(url string) error {
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return errors.New(message)
-		}
-		return errors.New("non 200 status code")
+		return reply.GetError()
 	}
 
 	return nil
@@ -234,9 +306,10 @@ func (wd *remoteWebDriver) Navigate(url string) error {
 
 func (wd *remoteWebDriver) GetCurrentURL() (string, error) {
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		GET,
-		fmt.Sprintf("%s/session/%s/url", wd.url, wd.session.GetID()),
+		fmt.Sprintf("/session/%s/url", wd.session.GetID()),
 		nil,
 	)
 
@@ -245,14 +318,10 @@ func (wd *remoteWebDriver) GetCurrentURL() (string, error) {
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return "", errors.New(message)
-		}
-		return "", errors.New("non 200 status code")
+		return "", reply.GetError()
 	}
 
-	url, err := reply.GetString("value.url", true)
+	url, err := reply.GetString("value", false)
 	if err != nil {
 		return "", err
 	}
@@ -263,9 +332,10 @@ func (wd *remoteWebDriver) GetCurrentURL() (string, error) {
 
 func (wd *remoteWebDriver) Back() error {
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		POST,
-		fmt.Sprintf("%s/session/%s/back", wd.url, wd.session.GetID()),
+		fmt.Sprintf("/session/%s/back", wd.session.GetID()),
 		nil,
 	)
 
@@ -274,11 +344,7 @@ func (wd *remoteWebDriver) Back() error {
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return errors.New(message)
-		}
-		return errors.New("non 200 status code")
+		return reply.GetError()
 	}
 
 	return nil
@@ -287,9 +353,10 @@ func (wd *remoteWebDriver) Back() error {
 
 func (wd *remoteWebDriver) Forward() error {
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		POST,
-		fmt.Sprintf("%s/session/%s/forward", wd.url, wd.session.GetID()),
+		fmt.Sprintf("/session/%s/forward", wd.session.GetID()),
 		nil,
 	)
 
@@ -298,11 +365,7 @@ func (wd *remoteWebDriver) Forward() error {
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return errors.New(message)
-		}
-		return errors.New("non 200 status code")
+		return reply.GetError()
 	}
 
 	return nil
@@ -311,9 +374,10 @@ func (wd *remoteWebDriver) Forward() error {
 
 func (wd *remoteWebDriver) Refresh() error {
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		POST,
-		fmt.Sprintf("%s/session/%s/refresh", wd.url, wd.session.GetID()),
+		fmt.Sprintf("/session/%s/refresh", wd.session.GetID()),
 		nil,
 	)
 
@@ -322,11 +386,7 @@ func (wd *remoteWebDriver) Refresh() error {
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return errors.New(message)
-		}
-		return errors.New("non 200 status code")
+		return reply.GetError()
 	}
 
 	return nil
@@ -335,9 +395,10 @@ func (wd *remoteWebDriver) Refresh() error {
 
 func (wd *remoteWebDriver) GetTitle() (string, error) {
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		GET,
-		fmt.Sprintf("%s/session/%s/title", wd.url, wd.session.GetID()),
+		fmt.Sprintf("/session/%s/title", wd.session.GetID()),
 		nil,
 	)
 
@@ -346,14 +407,10 @@ func (wd *remoteWebDriver) GetTitle() (string, error) {
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return "", errors.New(message)
-		}
-		return "", errors.New("non 200 status code")
+		return "", reply.GetError()
 	}
 
-	title, err := reply.GetString("value.title", true)
+	title, err := reply.GetString("value", false)
 	if err != nil {
 		return "", err
 	}
@@ -364,9 +421,10 @@ func (wd *remoteWebDriver) GetTitle() (string, error) {
 
 func (wd *remoteWebDriver) GetWindowHandle() (string, error) {
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		GET,
-		fmt.Sprintf("%s/session/%s/window", wd.url, wd.session.GetID()),
+		fmt.Sprintf("/session/%s/window", wd.session.GetID()),
 		nil,
 	)
 
@@ -375,14 +433,10 @@ func (wd *remoteWebDriver) GetWindowHandle() (string, error) {
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return "", errors.New(message)
-		}
-		return "", errors.New("non 200 status code")
+		return "", reply.GetError()
 	}
 
-	window, err := reply.GetString("value.window", true)
+	window, err := reply.GetString("value", false)
 	if err != nil {
 		return "", err
 	}
@@ -393,9 +447,10 @@ func (wd *remoteWebDriver) GetWindowHandle() (string, error) {
 
 func (wd *remoteWebDriver) CloseWindow() error {
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		DELETE,
-		fmt.Sprintf("%s/session/%s/window", wd.url, wd.session.GetID()),
+		fmt.Sprintf("/session/%s/window", wd.session.GetID()),
 		nil,
 	)
 
@@ -404,11 +459,7 @@ func (wd *remoteWebDriver) CloseWindow() error {
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return errors.New(message)
-		}
-		return errors.New("non 200 status code")
+		return reply.GetError()
 	}
 
 	return nil
@@ -417,9 +468,10 @@ func (wd *remoteWebDriver) CloseWindow() error {
 
 func (wd *remoteWebDriver) SwitchToWindow(window string) error {
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		POST,
-		fmt.Sprintf("%s/session/%s/window", wd.url, wd.session.GetID()),
+		fmt.Sprintf("/session/%s/window", wd.session.GetID()),
 		map[string]interface{}{"handle": window},
 	)
 
@@ -428,11 +480,7 @@ func (wd *remoteWebDriver) SwitchToWindow(window string) error {
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return errors.New(message)
-		}
-		return errors.New("non 200 status code")
+		return reply.GetError()
 	}
 
 	return nil
@@ -441,9 +489,10 @@ func (wd *remoteWebDriver) SwitchToWindow(window string) error {
 
 func (wd *remoteWebDriver) GetWindowHandles() ([]string, error) {
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		GET,
-		fmt.Sprintf("%s/session/%s/window/handles", wd.url, wd.session.GetID()),
+		fmt.Sprintf("/session/%s/window/handles", wd.session.GetID()),
 		nil,
 	)
 
@@ -452,14 +501,10 @@ func (wd *remoteWebDriver) GetWindowHandles() ([]string, error) {
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return nil, errors.New(message)
-		}
-		return nil, errors.New("non 200 status code")
+		return nil, reply.GetError()
 	}
 
-	handles, err := reply.GetStringSlice("value.handles", true)
+	handles, err := reply.GetStringSlice("value", false)
 	if err != nil {
 		return nil, err
 	}
@@ -470,9 +515,10 @@ func (wd *remoteWebDriver) GetWindowHandles() ([]string, error) {
 
 func (wd *remoteWebDriver) SwitchToFrame(id int) error {
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		POST,
-		fmt.Sprintf("%s/session/%s/frame", wd.url, wd.session.GetID()),
+		fmt.Sprintf("/session/%s/frame", wd.session.GetID()),
 		map[string]interface{}{"id": id},
 	)
 
@@ -481,11 +527,7 @@ func (wd *remoteWebDriver) SwitchToFrame(id int) error {
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return errors.New(message)
-		}
-		return errors.New("non 200 status code")
+		return reply.GetError()
 	}
 
 	return nil
@@ -494,9 +536,10 @@ func (wd *remoteWebDriver) SwitchToFrame(id int) error {
 
 func (wd *remoteWebDriver) SwitchToParentFrame() error {
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		POST,
-		fmt.Sprintf("%s/session/%s/frame/parent", wd.url, wd.session.GetID()),
+		fmt.Sprintf("/session/%s/frame/parent", wd.session.GetID()),
 		nil,
 	)
 
@@ -505,11 +548,7 @@ func (wd *remoteWebDriver) SwitchToParentFrame() error {
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return errors.New(message)
-		}
-		return errors.New("non 200 status code")
+		return reply.GetError()
 	}
 
 	return nil
@@ -518,9 +557,10 @@ func (wd *remoteWebDriver) SwitchToParentFrame() error {
 
 func (wd *remoteWebDriver) GetWindowRect() (*Rect, error) {
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		GET,
-		fmt.Sprintf("%s/session/%s/window/rect", wd.url, wd.session.GetID()),
+		fmt.Sprintf("/session/%s/window/rect", wd.session.GetID()),
 		nil,
 	)
 
@@ -529,11 +569,7 @@ func (wd *remoteWebDriver) GetWindowRect() (*Rect, error) {
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return nil, errors.New(message)
-		}
-		return nil, errors.New("non 200 status code received")
+		return nil, reply.GetError()
 	}
 
 	x, err := reply.GetFloat("value.x", true)
@@ -562,9 +598,10 @@ func (wd *remoteWebDriver) GetWindowRect() (*Rect, error) {
 
 func (wd *remoteWebDriver) SetWindowRect(rect *Rect) error {
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		POST,
-		fmt.Sprintf("%s/session/%s/window/rect", wd.url, wd.session.GetID()),
+		fmt.Sprintf("/session/%s/window/rect", wd.session.GetID()),
 		rect,
 	)
 
@@ -573,11 +610,7 @@ func (wd *remoteWebDriver) SetWindowRect(rect *Rect) error {
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return errors.New(message)
-		}
-		return errors.New("non 200 status code")
+		return reply.GetError()
 	}
 
 	return nil
@@ -586,9 +619,10 @@ func (wd *remoteWebDriver) SetWindowRect(rect *Rect) error {
 
 func (wd *remoteWebDriver) MaximizeWindow() error {
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		POST,
-		fmt.Sprintf("%s/session/%s/window/maximize", wd.url, wd.session.GetID()),
+		fmt.Sprintf("/session/%s/window/maximize", wd.session.GetID()),
 		nil,
 	)
 
@@ -597,11 +631,7 @@ func (wd *remoteWebDriver) MaximizeWindow() error {
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return errors.New(message)
-		}
-		return errors.New("non 200 status code")
+		return reply.GetError()
 	}
 
 	return nil
@@ -610,9 +640,10 @@ func (wd *remoteWebDriver) MaximizeWindow() error {
 
 func (wd *remoteWebDriver) MinimizeWindow() error {
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		POST,
-		fmt.Sprintf("%s/session/%s/window/minimize", wd.url, wd.session.GetID()),
+		fmt.Sprintf("/session/%s/window/minimize", wd.session.GetID()),
 		nil,
 	)
 
@@ -621,11 +652,7 @@ func (wd *remoteWebDriver) MinimizeWindow() error {
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return errors.New(message)
-		}
-		return errors.New("non 200 status code")
+		return reply.GetError()
 	}
 
 	return nil
@@ -634,9 +661,10 @@ func (wd *remoteWebDriver) MinimizeWindow() error {
 
 func (wd *remoteWebDriver) FullscreenWindow() error {
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		POST,
-		fmt.Sprintf("%s/session/%s/window/fullscreen", wd.url, wd.session.GetID()),
+		fmt.Sprintf("/session/%s/window/fullscreen", wd.session.GetID()),
 		nil,
 	)
 
@@ -645,11 +673,7 @@ func (wd *remoteWebDriver) FullscreenWindow() error {
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return errors.New(message)
-		}
-		return errors.New("non 200 status code")
+		return reply.GetError()
 	}
 
 	return nil
@@ -658,9 +682,10 @@ func (wd *remoteWebDriver) FullscreenWindow() error {
 
 func (wd *remoteWebDriver) FindElement(locator *by.Locator) (WebElement, error) {
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		POST,
-		fmt.Sprintf("%s/session/%s/element", wd.url, wd.session.GetID()),
+		fmt.Sprintf("/session/%s/element", wd.session.GetID()),
 		map[string]interface{}{
 			"using": locator.By,
 			"value": locator.Location,
@@ -672,11 +697,7 @@ func (wd *remoteWebDriver) FindElement(locator *by.Locator) (WebElement, error)
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return nil, errors.New(message)
-		}
-		return nil, errors.New("non 200 status code received")
+		return nil, reply.GetError()
 	}
 
 	elements, err := reply.GetStringMap("value", false)
@@ -694,9 +715,10 @@ func (wd *remoteWebDriver) FindElement(locator *by.Locator) (WebElement, error)
 
 func (wd *remoteWebDriver) FindElements(locator *by.Locator) ([]WebElement, error) {
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		POST,
-		fmt.Sprintf("%s/session/%s/element", wd.url, wd.session.GetID()),
+		fmt.Sprintf("/session/%s/elements", wd.session.GetID()),
 		map[string]interface{}{
 			"using": locator.By,
 			"value": locator.Location,
@@ -708,22 +730,22 @@ func (wd *remoteWebDriver) FindElements(locator *by.Locator) ([]WebElement, erro
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return nil, errors.New(message)
-		}
-		return nil, errors.New("non 200 status code received")
+		return nil, reply.GetError()
 	}
 
 	elements := make([]WebElement, 0)
 
-	foundElements, err := reply.GetStringMap("value", false)
+	foundElements := make([]map[string]string, 0)
+
+	err = reply.Unmarshal("value", false, &foundElements)
 	if err != nil {
 		return nil, err
 	}
 
-	for id, value := range foundElements {
-		elements = append(elements, &webElement{id: id, value: value, driver: wd})
+	for _, foundElement := range foundElements {
+		for id, value := range foundElement {
+			elements = append(elements, &webElement{id: id, value: value, driver: wd})
+		}
 	}
 
 	return elements, nil
@@ -737,9 +759,10 @@ func (wd *remoteWebDriver) FindElementFromElement(element WebElement, locator *b
 		return nil, errors.New("could not get web element info")
 	}
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		POST,
-		fmt.Sprintf("%s/session/%s/element/%s/element", wd.url, wd.session.GetID(), info.GetValue()),
+		fmt.Sprintf("/session/%s/element/%s/element", wd.session.GetID(), info.GetValue()),
 		map[string]interface{}{
 			"using": locator.By,
 			"value": locator.Location,
@@ -751,11 +774,7 @@ func (wd *remoteWebDriver) FindElementFromElement(element WebElement, locator *b
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return nil, errors.New(message)
-		}
-		return nil, errors.New("non 200 status code received")
+		return nil, reply.GetError()
 	}
 
 	elements, err := reply.GetStringMap("value", false)
@@ -778,9 +797,10 @@ func (wd *remoteWebDriver) FindElementsFromElement(element WebElement, locator *
 		return nil, errors.New("could not get web element info")
 	}
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		POST,
-		fmt.Sprintf("%s/session/%s/element/%s/element", wd.url, wd.session.GetID(), info.GetValue()),
+		fmt.Sprintf("/session/%s/element/%s/elements", wd.session.GetID(), info.GetValue()),
 		map[string]interface{}{
 			"using": locator.By,
 			"value": locator.Location,
@@ -792,22 +812,22 @@ func (wd *remoteWebDriver) FindElementsFromElement(element WebElement, locator *
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return nil, errors.New(message)
-		}
-		return nil, errors.New("non 200 status code received")
+		return nil, reply.GetError()
 	}
 
 	elements := make([]WebElement, 0)
 
-	foundElements, err := reply.GetStringMap("value", false)
+	foundElements := make([]map[string]string, 0)
+
+	err = reply.Unmarshal("value", false, &foundElements)
 	if err != nil {
 		return nil, err
 	}
 
-	for id, value := range foundElements {
-		elements = append(elements, &webElement{id: id, value: value, driver: wd})
+	for _, foundElement := range foundElements {
+		for id, value := range foundElement {
+			elements = append(elements, &webElement{id: id, value: value, driver: wd})
+		}
 	}
 
 	return elements, nil
@@ -815,9 +835,10 @@ func (wd *remoteWebDriver) FindElementsFromElement(element WebElement, locator *
 
 func (wd *remoteWebDriver) GetActiveElement() (WebElement, error) {
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		GET,
-		fmt.Sprintf("%s/session/%s/element/active", wd.url, wd.session.GetID()),
+		fmt.Sprintf("/session/%s/element/active", wd.session.GetID()),
 		nil,
 	)
 
@@ -826,11 +847,81 @@ func (wd *remoteWebDriver) GetActiveElement() (WebElement, error) {
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return nil, errors.New(message)
-		}
-		return nil, errors.New("non 200 status code received")
+		return nil, reply.GetError()
+	}
+
+	elements, err := reply.GetStringMap("value", false)
+	if err != nil {
+		return nil, err
+	}
+
+	for id, value := range elements {
+		return &webElement{id: id, value: value, driver: wd}, nil
+	}
+
+	return nil, errors.New("no element found")
+
+}
+
+//GetElementShadowRoot returns the shadow root attached to element
+func (wd *remoteWebDriver) GetElementShadowRoot(element WebElement) (ShadowRoot, error) {
+
+	info, ok := element.(WebElementInfo)
+	if !ok {
+		return nil, errors.New("could not get web element info")
+	}
+
+	reply, err := wd.execute(
+		wd.ctx,
+		GET,
+		fmt.Sprintf("/session/%s/element/%s/shadow", wd.session.GetID(), info.GetValue()),
+		nil,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if reply.StatusCode != 200 {
+		return nil, reply.GetError()
+	}
+
+	roots, err := reply.GetStringMap("value", false)
+	if err != nil {
+		return nil, err
+	}
+
+	for id, value := range roots {
+		return &shadowRoot{id: id, value: value, driver: wd}, nil
+	}
+
+	return nil, errors.New("no shadow root found")
+
+}
+
+func (wd *remoteWebDriver) FindElementFromShadowRoot(root ShadowRoot, locator *by.Locator) (WebElement, error) {
+
+	info, ok := root.(WebElementInfo)
+	if !ok {
+		return nil, errors.New("could not get shadow root info")
+	}
+
+	reply, err := wd.execute(
+		wd.ctx,
+		POST,
+		fmt.Sprintf("/session/%s/shadow/%s/element", wd.session.GetID(), info.GetValue()),
+		map[string]interface{}{
+			"using": locator.By,
+			"value": locator.Location,
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if reply.StatusCode != 200 {
+		return nil, reply.GetError()
 	}
 
 	elements, err := reply.GetStringMap("value", false)
@@ -846,6 +937,50 @@ func (wd *remoteWebDriver) GetActiveElement() (WebElement, error) {
 
 }
 
+func (wd *remoteWebDriver) FindElementsFromShadowRoot(root ShadowRoot, locator *by.Locator) ([]WebElement, error) {
+
+	info, ok := root.(WebElementInfo)
+	if !ok {
+		return nil, errors.New("could not get shadow root info")
+	}
+
+	reply, err := wd.execute(
+		wd.ctx,
+		POST,
+		fmt.Sprintf("/session/%s/shadow/%s/elements", wd.session.GetID(), info.GetValue()),
+		map[string]interface{}{
+			"using": locator.By,
+			"value": locator.Location,
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if reply.StatusCode != 200 {
+		return nil, reply.GetError()
+	}
+
+	elements := make([]WebElement, 0)
+
+	foundElements := make([]map[string]string, 0)
+
+	err = reply.Unmarshal("value", false, &foundElements)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, foundElement := range foundElements {
+		for id, value := range foundElement {
+			elements = append(elements, &webElement{id: id, value: value, driver: wd})
+		}
+	}
+
+	return elements, nil
+
+}
+
 func (wd *remoteWebDriver) IsElementSelected(element WebElement) (bool, error) {
 
 	info, ok := element.(WebElementInfo)
@@ -853,9 +988,10 @@ func (wd *remoteWebDriver) IsElementSelected(element WebElement) (bool, error) {
 		return false, errors.New("could not get web element info")
 	}
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		GET,
-		fmt.Sprintf("%s/session/%s/element/%s/selected", wd.url, wd.session.GetID(), info.GetValue()),
+		fmt.Sprintf("/session/%s/element/%s/selected", wd.session.GetID(), info.GetValue()),
 		nil,
 	)
 
@@ -864,11 +1000,7 @@ func (wd *remoteWebDriver) IsElementSelected(element WebElement) (bool, error) {
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return false, errors.New(message)
-		}
-		return false, errors.New("non 200 status code")
+		return false, reply.GetError()
 	}
 
 	selected, err := reply.GetBool("value", true)
@@ -887,9 +1019,10 @@ func (wd *remoteWebDriver) IsElementEnabled(element WebElement) (bool, error) {
 		return false, errors.New("could not get web element info")
 	}
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		GET,
-		fmt.Sprintf("%s/session/%s/element/%s/enabled", wd.url, wd.session.GetID(), info.GetValue()),
+		fmt.Sprintf("/session/%s/element/%s/enabled", wd.session.GetID(), info.GetValue()),
 		nil,
 	)
 
@@ -898,11 +1031,7 @@ func (wd *remoteWebDriver) IsElementEnabled(element WebElement) (bool, error) {
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return false, errors.New(message)
-		}
-		return false, errors.New("non 200 status code")
+		return false, reply.GetError()
 	}
 
 	enabled, err := reply.GetBool("value", true)
@@ -920,9 +1049,10 @@ func (wd *remoteWebDriver) GetElementAttribute(element WebElement, name string)
 		return "", errors.New("could not get web element info")
 	}
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		GET,
-		fmt.Sprintf("%s/session/%s/element/%s/attribute/%s", wd.url, wd.session.GetID(), info.GetValue(), name),
+		fmt.Sprintf("/session/%s/element/%s/attribute/%s", wd.session.GetID(), info.GetValue(), name),
 		nil,
 	)
 
@@ -931,14 +1061,15 @@ func (wd *remoteWebDriver) GetElementAttribute(element WebElement, name string)
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return "", errors.New(message)
-		}
-		return "", errors.New("non 200 status code")
+		return "", reply.GetError()
+	}
+
+	value, err := reply.Get("value", false)
+	if err != nil || value == nil {
+		return "", err
 	}
 
-	attribute, err := reply.GetString("value", true)
+	attribute, err := reply.GetString("value", false)
 	if err != nil {
 		return "", err
 	}
@@ -953,9 +1084,10 @@ func (wd *remoteWebDriver) GetElementProperty(element WebElement, name string) (
 		return "", errors.New("could not get web element info")
 	}
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		GET,
-		fmt.Sprintf("%s/session/%s/element/%s/attribute/%s", wd.url, wd.session.GetID(), info.GetValue(), name),
+		fmt.Sprintf("/session/%s/element/%s/property/%s", wd.session.GetID(), info.GetValue(), name),
 		nil,
 	)
 
@@ -964,19 +1096,20 @@ func (wd *remoteWebDriver) GetElementProperty(element WebElement, name string) (
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return "", errors.New(message)
-		}
-		return "", errors.New("non 200 status code")
+		return "", reply.GetError()
 	}
 
-	property, err := reply.GetString("value", true)
-	if err != nil {
+	value, err := reply.Get("value", false)
+	if err != nil || value == nil {
 		return "", err
 	}
 
-	return property, nil
+	//properties may be of any JSON type
+	if property, ok := value.(string); ok {
+		return property, nil
+	}
+
+	return fmt.Sprint(value), nil
 }
 func (wd *remoteWebDriver) GetElementCSS(element WebElement, name string) (string, error) {
 
@@ -985,9 +1118,10 @@ func (wd *remoteWebDriver) GetElementCSS(element WebElement, name string) (strin
 		return "", errors.New("could not get web element info")
 	}
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		GET,
-		fmt.Sprintf("%s/session/%s/element/%s/css/%s", wd.url, wd.session.GetID(), info.GetValue(), name),
+		fmt.Sprintf("/session/%s/element/%s/css/%s", wd.session.GetID(), info.GetValue(), name),
 		nil,
 	)
 
@@ -996,11 +1130,7 @@ func (wd *remoteWebDriver) GetElementCSS(element WebElement, name string) (strin
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return "", errors.New(message)
-		}
-		return "", errors.New("non 200 status code")
+		return "", reply.GetError()
 	}
 
 	property, err := reply.GetString("value", true)
@@ -1018,9 +1148,10 @@ func (wd *remoteWebDriver) GetElementText(element WebElement) (string, error) {
 		return "", errors.New("could not get web element info")
 	}
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		GET,
-		fmt.Sprintf("%s/session/%s/element/%s/text", wd.url, wd.session.GetID(), info.GetValue()),
+		fmt.Sprintf("/session/%s/element/%s/text", wd.session.GetID(), info.GetValue()),
 		nil,
 	)
 
@@ -1029,11 +1160,7 @@ func (wd *remoteWebDriver) GetElementText(element WebElement) (string, error) {
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return "", errors.New(message)
-		}
-		return "", errors.New("non 200 status code")
+		return "", reply.GetError()
 	}
 
 	property, err := reply.GetString("value", true)
@@ -1051,9 +1178,10 @@ func (wd *remoteWebDriver) GetElementTagName(element WebElement) (string, error)
 		return "", errors.New("could not get web element info")
 	}
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		GET,
-		fmt.Sprintf("%s/session/%s/element/%s/name", wd.url, wd.session.GetID(), info.GetValue()),
+		fmt.Sprintf("/session/%s/element/%s/name", wd.session.GetID(), info.GetValue()),
 		nil,
 	)
 
@@ -1062,14 +1190,10 @@ func (wd *remoteWebDriver) GetElementTagName(element WebElement) (string, error)
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return "", errors.New(message)
-		}
-		return "", errors.New("non 200 status code")
+		return "", reply.GetError()
 	}
 
-	name, err := reply.GetString("value.qualified name", true)
+	name, err := reply.GetString("value", false)
 	if err != nil {
 		return "", err
 	}
@@ -1084,9 +1208,10 @@ func (wd *remoteWebDriver) GetElementRect(element WebElement) (*Rect, error) {
 		return nil, errors.New("could not get web element info")
 	}
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		GET,
-		fmt.Sprintf("%s/session/%s/element/%s/rect", wd.url, wd.session.GetID(), info.GetValue()),
+		fmt.Sprintf("/session/%s/element/%s/rect", wd.session.GetID(), info.GetValue()),
 		nil,
 	)
 
@@ -1095,11 +1220,7 @@ func (wd *remoteWebDriver) GetElementRect(element WebElement) (*Rect, error) {
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return nil, errors.New(message)
-		}
-		return nil, errors.New("non 200 status code received")
+		return nil, reply.GetError()
 	}
 
 	x, err := reply.GetFloat("value.x", true)
@@ -1132,9 +1253,10 @@ func (wd *remoteWebDriver) ElementClick(element WebElement) error {
 		return errors.New("could not get web element info")
 	}
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		POST,
-		fmt.Sprintf("%s/session/%s/element/%s/click", wd.url, wd.session.GetID(), info.GetValue()),
+		fmt.Sprintf("/session/%s/element/%s/click", wd.session.GetID(), info.GetValue()),
 		make(map[string]interface{}, 0),
 	)
 
@@ -1143,11 +1265,7 @@ func (wd *remoteWebDriver) ElementClick(element WebElement) error {
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return errors.New(message)
-		}
-		return errors.New("non 200 status code received")
+		return reply.GetError()
 	}
 
 	return nil
@@ -1160,9 +1278,10 @@ func (wd *remoteWebDriver) ElementClear(element WebElement) error {
 		return errors.New("could not get web element info")
 	}
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		POST,
-		fmt.Sprintf("%s/session/%s/element/%s/clear", wd.url, wd.session.GetID(), info.GetValue()),
+		fmt.Sprintf("/session/%s/element/%s/clear", wd.session.GetID(), info.GetValue()),
 		nil,
 	)
 
@@ -1171,11 +1290,7 @@ func (wd *remoteWebDriver) ElementClear(element WebElement) error {
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return errors.New(message)
-		}
-		return errors.New("non 200 status code received")
+		return reply.GetError()
 	}
 
 	return nil
@@ -1188,9 +1303,10 @@ func (wd *remoteWebDriver) ElementSendKeys(element WebElement, keys string) erro
 		return errors.New("could not get web element info")
 	}
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		POST,
-		fmt.Sprintf("%s/session/%s/element/%s/value", wd.url, wd.session.GetID(), info.GetValue()),
+		fmt.Sprintf("/session/%s/element/%s/value", wd.session.GetID(), info.GetValue()),
 		map[string]interface{}{"text": keys},
 	)
 
@@ -1199,11 +1315,7 @@ func (wd *remoteWebDriver) ElementSendKeys(element WebElement, keys string) erro
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return errors.New(message)
-		}
-		return errors.New("non 200 status code received")
+		return reply.GetError()
 	}
 
 	return nil
@@ -1211,25 +1323,49 @@ func (wd *remoteWebDriver) ElementSendKeys(element WebElement, keys string) erro
 }
 
 func (wd *remoteWebDriver) ExecuteScript(script string, args ...interface{}) (interface{}, error) {
+	return wd.executeScript("sync", script, args)
+}
+
+//ExecuteAsyncScript executes script asynchronously in the current browsing context.
+//The script signals completion by calling the callback passed as its last argument,
+//arguments[arguments.length - 1], and fails if it does not do so within the session script timeout.
+func (wd *remoteWebDriver) ExecuteAsyncScript(script string, args ...interface{}) (interface{}, error) {
+	return wd.executeScript("async", script, args)
+}
+
+func (wd *remoteWebDriver) executeScript(mode string, script string, args []interface{}) (interface{}, error) {
+
+	arguments := make([]interface{}, len(args))
 
 	for i := 0; i < len(args); i++ {
 		if element, ok := args[i].(WebElement); ok {
 
-			info, ok := element.(WebElementInfo)
+			reference, err := elementReference(element)
+			if err != nil {
+				return nil, err
+			}
+
+			arguments[i] = reference
+		} else if root, ok := args[i].(ShadowRoot); ok {
+
+			info, ok := root.(WebElementInfo)
 			if !ok {
-				return nil, errors.New("could not get web element info")
+				return nil, errors.New("could not get shadow root info")
 			}
 
-			args[i] = map[string]interface{}{info.GetID(): info.GetValue()}
+			arguments[i] = map[string]interface{}{info.GetID(): info.GetValue()}
+		} else {
+			arguments[i] = args[i]
 		}
 	}
 
-	reply, err := ExecuteWDCommand(
+	reply, err := wd.execute(
+		wd.ctx,
 		POST,
-		fmt.Sprintf("%s/session/%s/execute/sync", wd.url, wd.session.GetID()),
+		fmt.Sprintf("/session/%s/execute/%s", wd.session.GetID(), mode),
 		map[string]interface{}{
 			"script": script,
-			"args":   args,
+			"args":   arguments,
 		},
 	)
 
@@ -1238,18 +1374,442 @@ func (wd *remoteWebDriver) ExecuteScript(script string, args ...interface{}) (in
 	}
 
 	if reply.StatusCode != 200 {
-		message, err := reply.GetString("value.message", true)
-		if err == nil {
-			return nil, errors.New(message)
+		return nil, reply.GetError()
+	}
+
+	value, err := reply.Get("value", false)
+	if err != nil {
+		return nil, err
+	}
+
+	return wd.decodeScriptResult(value), nil
+
+}
+
+//decodeScriptResult replaces the element and shadow root references found in value with web elements and shadow roots bound to wd
+func (wd *remoteWebDriver) decodeScriptResult(value interface{}) interface{} {
+
+	switch result := value.(type) {
+
+	case map[string]interface{}:
+
+		if len(result) == 1 {
+			for id, reference := range result {
+				if str, ok := reference.(string); ok && id == WebElementIdentifier {
+					return &webElement{id: id, value: str, driver: wd}
+				}
+				if str, ok := reference.(string); ok && id == ShadowRootIdentifier {
+					return &shadowRoot{id: id, value: str, driver: wd}
+				}
+			}
+		}
+
+		//new containers are built so the reply, which middlewares may hold, is left untouched
+		decoded := make(map[string]interface{}, len(result))
+		for key, item := range result {
+			decoded[key] = wd.decodeScriptResult(item)
+		}
+
+		return decoded
+
+	case []interface{}:
+
+		decoded := make([]interface{}, len(result))
+		for i, item := range result {
+			decoded[i] = wd.decodeScriptResult(item)
 		}
-		return nil, errors.New("non 200 status code received")
+
+		return decoded
+
+	}
+
+	return value
+
+}
+
+//GetPageSource returns the serialized DOM of the current browsing context
+func (wd *remoteWebDriver) GetPageSource() (string, error) {
+
+	reply, err := wd.execute(
+		wd.ctx,
+		GET,
+		fmt.Sprintf("/session/%s/source", wd.session.GetID()),
+		nil,
+	)
+
+	if err != nil {
+		return "", err
+	}
+
+	if reply.StatusCode != 200 {
+		return "", reply.GetError()
+	}
+
+	source, err := reply.GetString("value", false)
+	if err != nil {
+		return "", err
+	}
+
+	return source, nil
+
+}
+
+//PerformActions dispatches the action sequences built by actions
+func (wd *remoteWebDriver) PerformActions(actions *Actions) error {
+
+	if err := actions.Err(); err != nil {
+		return err
+	}
+
+	reply, err := wd.execute(
+		wd.ctx,
+		POST,
+		fmt.Sprintf("/session/%s/actions", wd.session.GetID()),
+		map[string]interface{}{"actions": actions.Sources()},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	if reply.StatusCode != 200 {
+		return reply.GetError()
+	}
+
+	return nil
+
+}
+
+//ReleaseActions releases all keys and pointer buttons that are currently depressed
+func (wd *remoteWebDriver) ReleaseActions() error {
+
+	reply, err := wd.execute(
+		wd.ctx,
+		DELETE,
+		fmt.Sprintf("/session/%s/actions", wd.session.GetID()),
+		nil,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	if reply.StatusCode != 200 {
+		return reply.GetError()
+	}
+
+	return nil
+
+}
+
+//SwitchToAlert returns the currently displayed user prompt, or an error if there is none
+func (wd *remoteWebDriver) SwitchToAlert() (Alert, error) {
+
+	_, err := wd.GetAlertText()
+	if err != nil {
+		return nil, err
+	}
+
+	return &alert{driver: wd}, nil
+
+}
+
+//DismissAlert dismisses the currently displayed user prompt
+func (wd *remoteWebDriver) DismissAlert() error {
+
+	reply, err := wd.execute(
+		wd.ctx,
+		POST,
+		fmt.Sprintf("/session/%s/alert/dismiss", wd.session.GetID()),
+		make(map[string]interface{}, 0),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	if reply.StatusCode != 200 {
+		return reply.GetError()
+	}
+
+	return nil
+
+}
+
+//AcceptAlert accepts the currently displayed user prompt
+func (wd *remoteWebDriver) AcceptAlert() error {
+
+	reply, err := wd.execute(
+		wd.ctx,
+		POST,
+		fmt.Sprintf("/session/%s/alert/accept", wd.session.GetID()),
+		make(map[string]interface{}, 0),
+	)
+
+	if err != nil {
+		return err
+	}
+
+	if reply.StatusCode != 200 {
+		return reply.GetError()
+	}
+
+	return nil
+
+}
+
+//GetAlertText returns the message of the currently displayed user prompt
+func (wd *remoteWebDriver) GetAlertText() (string, error) {
+
+	reply, err := wd.execute(
+		wd.ctx,
+		GET,
+		fmt.Sprintf("/session/%s/alert/text", wd.session.GetID()),
+		nil,
+	)
+
+	if err != nil {
+		return "", err
+	}
+
+	if reply.StatusCode != 200 {
+		return "", reply.GetError()
 	}
 
 	value, err := reply.Get("value", false)
+	if err != nil {
+		return "", err
+	}
+
+	//alerts without a message have a null value
+	text, _ := value.(string)
+
+	return text, nil
+
+}
+
+//SendAlertText sets the text field of the currently displayed window.prompt() user prompt
+func (wd *remoteWebDriver) SendAlertText(text string) error {
+
+	reply, err := wd.execute(
+		wd.ctx,
+		POST,
+		fmt.Sprintf("/session/%s/alert/text", wd.session.GetID()),
+		map[string]interface{}{"text": text},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	if reply.StatusCode != 200 {
+		return reply.GetError()
+	}
+
+	return nil
+
+}
+
+//GetCookies returns all cookies visible to the current page
+func (wd *remoteWebDriver) GetCookies() ([]*Cookie, error) {
+
+	reply, err := wd.execute(
+		wd.ctx,
+		GET,
+		fmt.Sprintf("/session/%s/cookie", wd.session.GetID()),
+		nil,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if reply.StatusCode != 200 {
+		return nil, reply.GetError()
+	}
+
+	cookies := make([]*Cookie, 0)
+
+	err = reply.Unmarshal("value", false, &cookies)
+	if err != nil {
+		return nil, err
+	}
+
+	return cookies, nil
+
+}
+
+//GetNamedCookie returns the cookie with the given name visible to the current page
+func (wd *remoteWebDriver) GetNamedCookie(name string) (*Cookie, error) {
+
+	reply, err := wd.execute(
+		wd.ctx,
+		GET,
+		fmt.Sprintf("/session/%s/cookie/%s", wd.session.GetID(), url.PathEscape(name)),
+		nil,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if reply.StatusCode != 200 {
+		return nil, reply.GetError()
+	}
+
+	cookie := new(Cookie)
+
+	err = reply.Unmarshal("value", false, cookie)
+	if err != nil {
+		return nil, err
+	}
+
+	return cookie, nil
+
+}
+
+//AddCookie adds a cookie to the cookie store of the current page's document
+func (wd *remoteWebDriver) AddCookie(cookie *Cookie) error {
+
+	reply, err := wd.execute(
+		wd.ctx,
+		POST,
+		fmt.Sprintf("/session/%s/cookie", wd.session.GetID()),
+		map[string]interface{}{"cookie": cookie},
+	)
+
+	if err != nil {
+		return err
+	}
+
+	if reply.StatusCode != 200 {
+		return reply.GetError()
+	}
+
+	return nil
+
+}
+
+//DeleteCookie deletes the cookie with the given name visible to the current page
+func (wd *remoteWebDriver) DeleteCookie(name string) error {
+
+	reply, err := wd.execute(
+		wd.ctx,
+		DELETE,
+		fmt.Sprintf("/session/%s/cookie/%s", wd.session.GetID(), url.PathEscape(name)),
+		nil,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	if reply.StatusCode != 200 {
+		return reply.GetError()
+	}
+
+	return nil
+
+}
+
+//DeleteAllCookies deletes all cookies visible to the current page
+func (wd *remoteWebDriver) DeleteAllCookies() error {
+
+	reply, err := wd.execute(
+		wd.ctx,
+		DELETE,
+		fmt.Sprintf("/session/%s/cookie", wd.session.GetID()),
+		nil,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	if reply.StatusCode != 200 {
+		return reply.GetError()
+	}
+
+	return nil
+
+}
+
+//TakeScreenshot captures the top-level browsing context's viewport
+func (wd *remoteWebDriver) TakeScreenshot() (Screenshot, error) {
+
+	reply, err := wd.execute(
+		wd.ctx,
+		GET,
+		fmt.Sprintf("/session/%s/screenshot", wd.session.GetID()),
+		nil,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if reply.StatusCode != 200 {
+		return nil, reply.GetError()
+	}
+
+	return decodeScreenshot(reply)
+
+}
+
+//TakeElementScreenshot captures the area of the bounding rectangle of element
+func (wd *remoteWebDriver) TakeElementScreenshot(element WebElement) (Screenshot, error) {
+
+	info, ok := element.(WebElementInfo)
+	if !ok {
+		return nil, errors.New("could not get web element info")
+	}
+
+	reply, err := wd.execute(
+		wd.ctx,
+		GET,
+		fmt.Sprintf("/session/%s/element/%s/screenshot", wd.session.GetID(), info.GetValue()),
+		nil,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if reply.StatusCode != 200 {
+		return nil, reply.GetError()
+	}
+
+	return decodeScreenshot(reply)
+
+}
+
+//PrintPage renders the current page as a paginated PDF document and returns its bytes
+func (wd *remoteWebDriver) PrintPage(options *PrintOptions) ([]byte, error) {
+
+	if options == nil {
+		options = new(PrintOptions)
+	}
+
+	reply, err := wd.execute(
+		wd.ctx,
+		POST,
+		fmt.Sprintf("/session/%s/print", wd.session.GetID()),
+		options,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if reply.StatusCode != 200 {
+		return nil, reply.GetError()
+	}
+
+	encoded, err := reply.GetString("value", false)
 	if err != nil {
 		return nil, err
 	}
 
-	return value, nil
+	return base64.StdEncoding.DecodeString(encoded)
 
 }
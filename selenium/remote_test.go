@@ -0,0 +1,261 @@
+package selenium
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"./by"
+	"github.com/stretchr/testify/require"
+)
+
+//commandServer is a fake remote end recording the last command received and replying with status and value
+type commandServer struct {
+	*httptest.Server
+	command string
+	payload map[string]interface{}
+	status  int
+	value   interface{}
+}
+
+//newCommandServer starts a commandServer replying to every command with a null value, and a driver of its session "1"
+func newCommandServer(t *testing.T) (*commandServer, *remoteWebDriver) {
+
+	server := &commandServer{status: http.StatusOK}
+
+	server.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		server.command = r.Method + " " + r.URL.Path
+		server.payload = nil
+		if r.Method == http.MethodPost {
+			require.NoErrorf(t, json.NewDecoder(r.Body).Decode(&server.payload), "Command payload should be JSON.")
+		}
+
+		w.WriteHeader(server.status)
+		json.NewEncoder(w).Encode(map[string]interface{}{"value": server.value})
+
+	}))
+
+	wd := NewRemote(server.URL, NewCapabilities())
+	wd.SetSession("1", nil)
+
+	return server, wd
+
+}
+
+func TestDecodeScriptResult(t *testing.T) {
+
+	wd := NewRemote("http://127.0.0.1:4444", NewCapabilities())
+
+	raw := map[string]interface{}{
+		"count": float64(2),
+		"items": []interface{}{
+			map[string]interface{}{WebElementIdentifier: "a"},
+			map[string]interface{}{ShadowRootIdentifier: "b"},
+		},
+	}
+
+	value := wd.decodeScriptResult(raw)
+	require.Equalf(t, map[string]interface{}{WebElementIdentifier: "a"}, raw["items"].([]interface{})[0], "Decoded reply should be left untouched.")
+
+	references := ScriptResultReferences(value)
+	require.Lenf(t, references, 2, "Script result should contain both references.")
+
+	result, ok := value.(map[string]interface{})
+	require.Truef(t, ok, "Script result should remain an object.")
+	require.Equalf(t, float64(2), result["count"], "Non reference values should be left untouched.")
+
+	items := result["items"].([]interface{})
+	require.Equalf(t, &webElement{id: WebElementIdentifier, value: "a", driver: wd}, items[0], "Element reference should become a WebElement.")
+	require.Equalf(t, &shadowRoot{id: ShadowRootIdentifier, value: "b", driver: wd}, items[1], "Shadow root reference should become a ShadowRoot.")
+
+}
+
+func TestWithContext(t *testing.T) {
+
+	done := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-done
+	}))
+	defer server.Close()
+	defer close(done)
+
+	wd := NewRemote(server.URL, NewCapabilities())
+	wd.SetSession("1", nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := wd.WithContext(ctx).GetTitle()
+	require.Truef(t, errors.Is(err, context.DeadlineExceeded), "Command should be aborted when its context is done.")
+
+}
+
+func TestWithContextElements(t *testing.T) {
+
+	server, wd := newCommandServer(t)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	server.value = map[string]interface{}{WebElementIdentifier: "a"}
+	element, err := wd.WithContext(ctx).FindElement(by.ID("a"))
+	require.NoErrorf(t, err, "Element should be found.")
+
+	cancel()
+
+	server.value = nil
+	err = element.Click()
+	require.Truef(t, errors.Is(err, context.Canceled), "Element should stay bound to the context it was found with.")
+
+	require.NoErrorf(t, wd.ElementClick(element), "Element should be usable through another driver.")
+	require.Equalf(t, "POST /session/1/element/a/click", server.command, "Element click should be sent for the element.")
+
+	require.NoErrorf(t, element.(WebElementUpdater).SetDriver(wd), "Element should be rebound.")
+	require.NoErrorf(t, element.Click(), "Rebound element should use the driver it is bound to.")
+
+}
+
+func TestExecuteAsyncScript(t *testing.T) {
+
+	server, wd := newCommandServer(t)
+	defer server.Close()
+
+	server.value = "done"
+
+	element := &webElement{id: "element-6066-11e4-a52e-4f735466cecf", value: "e1", driver: wd}
+
+	value, err := wd.ExecuteAsyncScript("arguments[arguments.length - 1](arguments[0].id)", element, 2)
+	require.NoErrorf(t, err, "Async script should be executed.")
+	require.Equalf(t, "done", value, "Value passed to the callback should be returned.")
+	require.Equalf(t, "POST /session/1/execute/async", server.command, "Async script should be posted.")
+	require.Equalf(t, map[string]interface{}{
+		"script": "arguments[arguments.length - 1](arguments[0].id)",
+		"args":   []interface{}{map[string]interface{}{"element-6066-11e4-a52e-4f735466cecf": "e1"}, float64(2)},
+	}, server.payload, "Elements should be sent as references.")
+
+	server.status = http.StatusInternalServerError
+	server.value = map[string]interface{}{"error": "script timeout", "message": "callback was not called"}
+
+	_, err = wd.ExecuteAsyncScript("")
+	require.Truef(t, errors.Is(err, ScriptTimeout), "Script timeout should be reported.")
+
+}
+
+func TestGetPageSource(t *testing.T) {
+
+	server, wd := newCommandServer(t)
+	defer server.Close()
+
+	server.value = "<html><body></body></html>"
+
+	source, err := wd.GetPageSource()
+	require.NoErrorf(t, err, "Page source should be read.")
+	require.Equalf(t, "<html><body></body></html>", source, "Page source should be the reply value.")
+	require.Equalf(t, "GET /session/1/source", server.command, "Page source should be requested.")
+
+}
+
+func TestShadowRoot(t *testing.T) {
+
+	server, wd := newCommandServer(t)
+	defer server.Close()
+
+	element := &webElement{id: WebElementIdentifier, value: "host", driver: wd}
+
+	server.value = map[string]interface{}{ShadowRootIdentifier: "root"}
+
+	root, err := element.GetShadowRoot()
+	require.NoErrorf(t, err, "Shadow root should be returned.")
+	require.Equalf(t, "GET /session/1/element/host/shadow", server.command, "Shadow root should be requested.")
+	require.Equalf(t, &shadowRoot{id: ShadowRootIdentifier, value: "root", driver: wd}, root, "Shadow root reference should be decoded.")
+
+	server.value = map[string]interface{}{WebElementIdentifier: "inner"}
+
+	found, err := root.FindElement(by.CSS("span"))
+	require.NoErrorf(t, err, "Element should be found in the shadow root.")
+	require.Equalf(t, "POST /session/1/shadow/root/element", server.command, "Element should be searched from the shadow root.")
+	require.Equalf(t, map[string]interface{}{"using": "css selector", "value": "span"}, server.payload, "Locator should be sent.")
+	require.Equalf(t, &webElement{id: WebElementIdentifier, value: "inner", driver: wd}, found, "Element reference should be decoded.")
+
+	server.value = []interface{}{
+		map[string]interface{}{WebElementIdentifier: "first"},
+		map[string]interface{}{WebElementIdentifier: "second"},
+	}
+
+	elements, err := root.FindElements(by.CSS("span"))
+	require.NoErrorf(t, err, "Elements should be found in the shadow root.")
+	require.Equalf(t, "POST /session/1/shadow/root/elements", server.command, "Elements should be searched from the shadow root.")
+	require.Lenf(t, elements, 2, "Every element reference should be decoded.")
+
+	server.status = http.StatusNotFound
+	server.value = map[string]interface{}{"error": "no such shadow root", "message": "element has no shadow root"}
+
+	_, err = element.GetShadowRoot()
+	require.Errorf(t, err, "Missing shadow root should be reported.")
+
+}
+
+func TestW3CReplyValues(t *testing.T) {
+
+	server, wd := newCommandServer(t)
+	defer server.Close()
+
+	server.value = "https://example.com/"
+	url, err := wd.GetCurrentURL()
+	require.NoErrorf(t, err, "URL should be read.")
+	require.Equalf(t, "https://example.com/", url, "URL should be the reply value.")
+
+	server.value = "Example Domain"
+	title, err := wd.GetTitle()
+	require.NoErrorf(t, err, "Title should be read.")
+	require.Equalf(t, "Example Domain", title, "Title should be the reply value.")
+
+	server.value = "window-1"
+	handle, err := wd.GetWindowHandle()
+	require.NoErrorf(t, err, "Window handle should be read.")
+	require.Equalf(t, "window-1", handle, "Window handle should be the reply value.")
+
+	server.value = []interface{}{"window-1", "window-2"}
+	handles, err := wd.GetWindowHandles()
+	require.NoErrorf(t, err, "Window handles should be read.")
+	require.Equalf(t, []string{"window-1", "window-2"}, handles, "Window handles should be decoded from a JSON array.")
+
+	server.value = []interface{}{
+		map[string]interface{}{WebElementIdentifier: "first"},
+		map[string]interface{}{WebElementIdentifier: "second"},
+	}
+	elements, err := wd.FindElements(by.CSS("li"))
+	require.NoErrorf(t, err, "Elements should be found.")
+	require.Equalf(t, "POST /session/1/elements", server.command, "Elements should be searched with Find Elements.")
+	require.Lenf(t, elements, 2, "Every element reference should be decoded.")
+
+	element := &webElement{id: WebElementIdentifier, value: "list", driver: wd}
+
+	elements, err = wd.FindElementsFromElement(element, by.CSS("li"))
+	require.NoErrorf(t, err, "Elements should be found from the element.")
+	require.Equalf(t, "POST /session/1/element/list/elements", server.command, "Elements should be searched with Find Elements From Element.")
+	require.Lenf(t, elements, 2, "Every element reference should be decoded.")
+
+	server.value = float64(3)
+	property, err := wd.GetElementProperty(element, "childElementCount")
+	require.NoErrorf(t, err, "Property should be read.")
+	require.Equalf(t, "GET /session/1/element/list/property/childElementCount", server.command, "Property should be requested with Get Element Property.")
+	require.Equalf(t, "3", property, "Non string properties should be formatted.")
+
+	server.value = nil
+	attribute, err := wd.GetElementAttribute(element, "title")
+	require.NoErrorf(t, err, "Missing attribute should not be an error.")
+	require.Equalf(t, "", attribute, "Missing attribute should be empty.")
+
+	server.value = "ul"
+	name, err := wd.GetElementTagName(element)
+	require.NoErrorf(t, err, "Tag name should be read.")
+	require.Equalf(t, "ul", name, "Tag name should be the reply value.")
+
+}
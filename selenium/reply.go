@@ -1,7 +1,9 @@
 package selenium
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"strings"
 )
 
@@ -16,6 +18,8 @@ type Reply struct {
 type Reply struct {
 	StatusCode int
 	Data       map[string]interface{}
+	Method     Method
+	Endpoint   string
 }
 
 func (reply *Reply) Get(name string, useDotNotation bool) (interface{}, error) {
@@ -127,6 +131,20 @@ func (reply *Reply) GetStringSlice(name string, useDotNotation bool) ([]string,
 	if str, ok := value.([]string); ok {
 		return str, nil
 	}
+
+	//decoded JSON arrays are []interface{}
+	if items, ok := value.([]interface{}); ok {
+		strs := make([]string, len(items))
+		for i, item := range items {
+			str, ok := item.(string)
+			if !ok {
+				return nil, errors.New("could not parse string slice: " + name)
+			}
+			strs[i] = str
+		}
+		return strs, nil
+	}
+
 	return nil, errors.New("could not parse string slice: " + name)
 
 }
@@ -171,3 +189,43 @@ func (reply *Reply) GetStringMap(name string, useDotNotation bool) (map[string]s
 	return nil, errors.New("could not parse string map(2): " + name)
 
 }
+
+//Unmarshal decodes the named value of the reply into v
+func (reply *Reply) Unmarshal(name string, useDotNotation bool, v interface{}) error {
+
+	value, err := reply.Get(name, useDotNotation)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+
+}
+
+//GetError returns the typed remote error described by the value of an unsuccessful reply
+func (reply *Reply) GetError() error {
+
+	remoteError := &Error{StatusCode: reply.StatusCode, Method: reply.Method, Endpoint: reply.Endpoint}
+
+	err := reply.Unmarshal("value", false, remoteError)
+	if err != nil || remoteError.Message == "" {
+		remoteError.Message = fmt.Sprintf("non 200 status code received: %d", reply.StatusCode)
+	}
+
+	if remoteError.Code == "" {
+		remoteError.Code = UnknownError
+	}
+
+	if remoteError.Code == UnexpectedAlertOpen {
+		text, _ := reply.GetString("value.data.text", true)
+		return &UnexpectedAlertOpenError{Remote: remoteError, Text: text}
+	}
+
+	return newTypedError(remoteError)
+
+}
@@ -0,0 +1,38 @@
+package selenium
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/png"
+	"io/ioutil"
+)
+
+//Screenshot holds the PNG encoded bytes returned by the screenshot commands
+type Screenshot []byte
+
+//Image decodes the screenshot into an image.Image
+func (screenshot Screenshot) Image() (image.Image, error) {
+	return png.Decode(bytes.NewReader(screenshot))
+}
+
+//Save writes the screenshot to the named PNG file
+func (screenshot Screenshot) Save(path string) error {
+	return ioutil.WriteFile(path, screenshot, 0644)
+}
+
+func decodeScreenshot(reply *Reply) (Screenshot, error) {
+
+	encoded, err := reply.GetString("value", false)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	return Screenshot(data), nil
+
+}
@@ -0,0 +1,47 @@
+package selenium
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/png"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScreenshots(t *testing.T) {
+
+	encoded := new(bytes.Buffer)
+	require.NoErrorf(t, png.Encode(encoded, image.NewRGBA(image.Rect(0, 0, 4, 3))), "Test image should be encoded.")
+
+	server, wd := newCommandServer(t)
+	defer server.Close()
+
+	server.value = base64.StdEncoding.EncodeToString(encoded.Bytes())
+
+	screenshot, err := wd.TakeScreenshot()
+	require.NoErrorf(t, err, "Screenshot should be taken.")
+	require.Equalf(t, "GET /session/1/screenshot", server.command, "Screenshot should be requested.")
+	require.Equalf(t, encoded.Bytes(), []byte(screenshot), "Screenshot should be decoded from base64.")
+
+	img, err := screenshot.Image()
+	require.NoErrorf(t, err, "Screenshot should decode as PNG.")
+	require.Equalf(t, image.Rect(0, 0, 4, 3), img.Bounds(), "Decoded image should keep its size.")
+
+	path := filepath.Join(t.TempDir(), "page.png")
+	require.NoErrorf(t, screenshot.Save(path), "Screenshot should be saved.")
+
+	element := &webElement{id: "element-6066-11e4-a52e-4f735466cecf", value: "e1", driver: wd}
+
+	_, err = wd.TakeElementScreenshot(element)
+	require.NoErrorf(t, err, "Element screenshot should be taken.")
+	require.Equalf(t, "GET /session/1/element/e1/screenshot", server.command, "Element screenshot should be requested.")
+
+	server.value = "not base64!"
+
+	_, err = wd.TakeScreenshot()
+	require.Errorf(t, err, "Invalid screenshot data should be reported.")
+
+}
@@ -0,0 +1,42 @@
+package seleniumtest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/png"
+
+	"../../selenium"
+)
+
+//pdf is the base64 encoded document returned by "Print Page"
+var pdf = base64.StdEncoding.EncodeToString([]byte("%PDF-1.4\n1 0 obj << /Type /Catalog /Pages 2 0 R >> endobj\n2 0 obj << /Type /Pages /Kids [] /Count 0 >> endobj\ntrailer << /Root 1 0 R >>\n%%EOF\n"))
+
+//screenshot returns a base64 encoded blank PNG of the given size
+func screenshot(width int, height int) (string, error) {
+
+	if width <= 0 || height <= 0 {
+		return "", newError(selenium.UnableToCaptureScreen, "unable to capture screen: element has no size")
+	}
+
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+
+	buffer := new(bytes.Buffer)
+	if err := png.Encode(buffer, img); err != nil {
+		return "", newError(selenium.UnableToCaptureScreen, "unable to capture screen: %v", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(buffer.Bytes()), nil
+
+}
+
+func rectValue(rect selenium.Rect) map[string]interface{} {
+	return map[string]interface{}{"x": rect.X, "y": rect.Y, "width": rect.Width, "height": rect.Height}
+}
+
+func timeoutsValue(timeouts selenium.Timeouts) map[string]interface{} {
+	return map[string]interface{}{"script": timeouts.Script, "pageLoad": timeouts.PageLoad, "implicit": timeouts.Implicit}
+}
@@ -0,0 +1,146 @@
+package seleniumtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"../websocket"
+)
+
+//CDPMessage is a command, reply or event of the Chrome DevTools Protocol exchanged with a CDPServer
+type CDPMessage struct {
+	ID        int64                  `json:"id,omitempty"`
+	Method    string                 `json:"method,omitempty"`
+	Params    map[string]interface{} `json:"params,omitempty"`
+	SessionID string                 `json:"sessionId,omitempty"`
+	Result    map[string]interface{} `json:"result,omitempty"`
+	Error     map[string]interface{} `json:"error,omitempty"`
+}
+
+//CDPHandler answers a command with its result. Events sent with emit reach the client before the reply, as with browsers.
+//A nil result is answered with a "method not found" error.
+type CDPHandler func(command *CDPMessage, emit func(event *CDPMessage)) map[string]interface{}
+
+//CDPServer is an in-process fake browser DevTools endpoint. It lists its WebSocket URL at /json/version,
+//so it can be used as the debuggerAddress of a Chromium session.
+//
+//By default it attaches the session "page-session" with Target.attachToTarget, and auto attaches the sessions
+//"page-session" and "popup-session" of two page targets with Target.setAutoAttach. Page.enable emits
+//Page.loadEventFired for the session and the browser, and Browser.getVersion returns a Chrome product.
+type CDPServer struct {
+	*httptest.Server
+
+	mutex    sync.Mutex
+	handlers map[string]CDPHandler
+}
+
+//NewCDPServer starts and returns a fake DevTools endpoint. The caller should call Close when finished.
+func NewCDPServer() *CDPServer {
+
+	server := &CDPServer{handlers: make(map[string]CDPHandler)}
+	server.Server = httptest.NewServer(server)
+
+	server.Handle("Target.attachToTarget", func(command *CDPMessage, emit func(event *CDPMessage)) map[string]interface{} {
+		return map[string]interface{}{"sessionId": "page-session"}
+	})
+
+	server.Handle("Target.setAutoAttach", func(command *CDPMessage, emit func(event *CDPMessage)) map[string]interface{} {
+		//an existing page and a popup opened later are attached
+		for _, target := range []string{"page", "popup"} {
+			params := map[string]interface{}{"sessionId": target + "-session", "targetInfo": map[string]interface{}{"targetId": target, "type": "page"}}
+			emit(&CDPMessage{Method: "Target.attachedToTarget", Params: params})
+		}
+		return map[string]interface{}{}
+	})
+
+	server.Handle("Page.enable", func(command *CDPMessage, emit func(event *CDPMessage)) map[string]interface{} {
+		emit(&CDPMessage{Method: "Page.loadEventFired", SessionID: command.SessionID, Params: map[string]interface{}{"timestamp": 1.5}})
+		emit(&CDPMessage{Method: "Page.loadEventFired", Params: map[string]interface{}{"timestamp": 2.5}})
+		return map[string]interface{}{}
+	})
+
+	server.Handle("Browser.getVersion", func(command *CDPMessage, emit func(event *CDPMessage)) map[string]interface{} {
+		return map[string]interface{}{"product": "Chrome/120.0.6099.109"}
+	})
+
+	empty := func(command *CDPMessage, emit func(event *CDPMessage)) map[string]interface{} {
+		return map[string]interface{}{}
+	}
+	server.Handle("Runtime.enable", empty)
+	server.Handle("Runtime.runIfWaitingForDebugger", empty)
+
+	return server
+
+}
+
+//Handle sets the handler of the command named method, replacing the default one if any
+func (server *CDPServer) Handle(method string, handler CDPHandler) {
+
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	server.handlers[method] = handler
+
+}
+
+//Address returns the host and port of the server, as found in the debuggerAddress capability
+func (server *CDPServer) Address() string {
+	return strings.TrimPrefix(server.URL, "http://")
+}
+
+//WebSocketURL returns the URL of the browser target
+func (server *CDPServer) WebSocketURL() string {
+	return "ws://" + server.Address() + "/devtools/browser/1"
+}
+
+func (server *CDPServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	if r.URL.Path == "/json/version" {
+		json.NewEncoder(w).Encode(map[string]interface{}{"webSocketDebuggerUrl": server.WebSocketURL()})
+		return
+	}
+
+	ws, err := websocket.Upgrade(w, r)
+	if err != nil {
+		return
+	}
+	defer ws.Close()
+
+	emit := func(event *CDPMessage) {
+		data, _ := json.Marshal(event)
+		ws.WriteMessage(data)
+	}
+
+	for {
+
+		data, err := ws.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		command := new(CDPMessage)
+		if json.Unmarshal(data, command) != nil {
+			continue
+		}
+
+		server.mutex.Lock()
+		handler := server.handlers[command.Method]
+		server.mutex.Unlock()
+
+		reply := &CDPMessage{ID: command.ID, SessionID: command.SessionID}
+		if handler != nil {
+			reply.Result = handler(command, emit)
+		}
+		if reply.Result == nil {
+			reply.Error = map[string]interface{}{"code": -32601, "message": "'" + command.Method + "' wasn't found"}
+		}
+
+		data, _ = json.Marshal(reply)
+		ws.WriteMessage(data)
+
+	}
+
+}
@@ -0,0 +1,288 @@
+package seleniumtest
+
+import (
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+
+	"../../selenium"
+)
+
+//Element is a node of the in-memory DOM served by the fake remote end
+type Element struct {
+	Tag        string
+	Attributes map[string]string
+	Properties map[string]interface{}
+	CSS        map[string]string
+	Text       string
+	Value      string
+	Rect       selenium.Rect
+	Hidden     bool
+	Disabled   bool
+	Selected   bool
+	Children   []*Element
+	Shadow     []*Element
+	OnClick    func(session *Session, element *Element)
+	OnSubmit   func(session *Session, form *Element)
+
+	id       string
+	shadowID string
+	parent   *Element
+}
+
+//NewElement returns an element with the given tag name and children
+func NewElement(tag string, children ...*Element) *Element {
+	return &Element{
+		Tag:        tag,
+		Attributes: make(map[string]string),
+		Properties: make(map[string]interface{}),
+		CSS:        make(map[string]string),
+		Children:   children,
+	}
+}
+
+//WithAttribute sets an attribute of the element
+func (e *Element) WithAttribute(name string, value string) *Element {
+	e.Attributes[name] = value
+	return e
+}
+
+//WithProperty sets a property of the element
+func (e *Element) WithProperty(name string, value interface{}) *Element {
+	e.Properties[name] = value
+	return e
+}
+
+//WithCSS sets a computed style property of the element
+func (e *Element) WithCSS(name string, value string) *Element {
+	e.CSS[name] = value
+	return e
+}
+
+//WithText sets the text of the element
+func (e *Element) WithText(text string) *Element {
+	e.Text = text
+	return e
+}
+
+//WithRect sets the bounding rectangle of the element
+func (e *Element) WithRect(x int, y int, width int, height int) *Element {
+	e.Rect = selenium.Rect{X: x, Y: y, Width: width, Height: height}
+	return e
+}
+
+//WithShadowRoot attaches a shadow root holding children to the element
+func (e *Element) WithShadowRoot(children ...*Element) *Element {
+	e.Shadow = append(make([]*Element, 0), children...)
+	return e
+}
+
+//WithOnClick sets the callback run when the element is clicked
+func (e *Element) WithOnClick(onClick func(session *Session, element *Element)) *Element {
+	e.OnClick = onClick
+	return e
+}
+
+//WithOnSubmit sets the callback run when the form is submitted
+func (e *Element) WithOnSubmit(onSubmit func(session *Session, form *Element)) *Element {
+	e.OnSubmit = onSubmit
+	return e
+}
+
+//GetID returns the web element reference of the element, empty until it is loaded in a window
+func (e *Element) GetID() string { return e.id }
+
+//Parent returns the parent of the element
+func (e *Element) Parent() *Element { return e.parent }
+
+//GetAttribute returns the named attribute and whether it is set
+func (e *Element) GetAttribute(name string) (string, bool) {
+	value, ok := e.Attributes[name]
+	return value, ok
+}
+
+//GetProperty returns the named property, falling back to the element value, text and attributes
+func (e *Element) GetProperty(name string) interface{} {
+
+	if value, ok := e.Properties[name]; ok {
+		return value
+	}
+
+	switch name {
+	case "value":
+		return e.Value
+	case "textContent", "innerText":
+		return e.VisibleText()
+	case "checked", "selected":
+		return e.Selected
+	case "disabled":
+		return e.Disabled
+	case "tagName":
+		return strings.ToUpper(e.Tag)
+	}
+
+	if value, ok := e.Attributes[name]; ok {
+		return value
+	}
+
+	return nil
+
+}
+
+//VisibleText returns the rendered text of the element and its displayed descendants
+func (e *Element) VisibleText() string {
+
+	if e.Hidden {
+		return ""
+	}
+
+	parts := make([]string, 0)
+	if e.Text != "" {
+		parts = append(parts, e.Text)
+	}
+
+	for _, child := range e.Children {
+		if text := child.VisibleText(); text != "" {
+			parts = append(parts, text)
+		}
+	}
+
+	return strings.Join(parts, "\n")
+
+}
+
+//Find returns the first descendant matching the W3C locator strategy and selector
+func (e *Element) Find(using string, value string) (*Element, error) {
+
+	elements, err := e.FindAll(using, value)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(elements) == 0 {
+		return nil, nil
+	}
+
+	return elements[0], nil
+
+}
+
+//FindAll returns the descendants matching the W3C locator strategy and selector in document order
+func (e *Element) FindAll(using string, value string) ([]*Element, error) {
+	return findAll(e.Children, e, using, value)
+}
+
+//walk calls fn for every element of the tree rooted at e, depth first, without entering shadow roots
+func (e *Element) walk(fn func(element *Element)) {
+	fn(e)
+	for _, child := range e.Children {
+		child.walk(fn)
+	}
+}
+
+func (e *Element) clone(parent *Element) *Element {
+
+	c := *e
+	c.parent = parent
+	c.Attributes = copyStrings(e.Attributes)
+	c.Properties = make(map[string]interface{}, len(e.Properties))
+	for k, v := range e.Properties {
+		c.Properties[k] = v
+	}
+	c.CSS = copyStrings(e.CSS)
+
+	c.Children = make([]*Element, len(e.Children))
+	for i, child := range e.Children {
+		c.Children[i] = child.clone(&c)
+	}
+
+	if e.Shadow != nil {
+		c.Shadow = make([]*Element, len(e.Shadow))
+		for i, child := range e.Shadow {
+			c.Shadow[i] = child.clone(&c)
+		}
+	}
+
+	return &c
+
+}
+
+func (e *Element) rect() selenium.Rect {
+
+	if e.Hidden {
+		return selenium.Rect{X: e.Rect.X, Y: e.Rect.Y}
+	}
+
+	if e.Rect.Width == 0 && e.Rect.Height == 0 {
+		return selenium.Rect{X: e.Rect.X, Y: e.Rect.Y, Width: 100, Height: 20}
+	}
+
+	return e.Rect
+
+}
+
+func (e *Element) isHidden() bool {
+	for element := e; element != nil; element = element.parent {
+		if element.Hidden {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Element) html(builder *strings.Builder) {
+
+	builder.WriteString("<" + e.Tag)
+
+	names := make([]string, 0, len(e.Attributes))
+	for name := range e.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		builder.WriteString(fmt.Sprintf(" %s=\"%s\"", name, html.EscapeString(e.Attributes[name])))
+	}
+
+	builder.WriteString(">")
+	builder.WriteString(html.EscapeString(e.Text))
+
+	for _, child := range e.Children {
+		child.html(builder)
+	}
+
+	builder.WriteString("</" + e.Tag + ">")
+
+}
+
+//Page is a document served by the fake remote end when its URL is navigated to
+type Page struct {
+	Title string
+	Body  []*Element
+}
+
+//NewPage returns a page with the given title and body elements
+func NewPage(title string, body ...*Element) *Page {
+	return &Page{Title: title, Body: body}
+}
+
+//document builds a fresh DOM tree for the page
+func (page *Page) document() *Element {
+
+	root := NewElement("html",
+		NewElement("head", NewElement("title").WithText(page.Title)),
+		NewElement("body", page.Body...),
+	)
+
+	return root.clone(nil)
+
+}
+
+func copyStrings(m map[string]string) map[string]string {
+	c := make(map[string]string, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
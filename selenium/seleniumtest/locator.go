@@ -0,0 +1,248 @@
+package seleniumtest
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var errInvalidSelector = errors.New("invalid selector")
+
+//findAll returns the elements below roots matching the locator. context is the element the search starts from.
+//The fake remote end understands simple CSS selectors (compound selectors joined by descendant combinators),
+//tag names, link texts and the XPath forms //tag[@attr='value'], //tag[text()='value'] and ./ancestor-or-self::tag.
+func findAll(roots []*Element, context *Element, using string, value string) ([]*Element, error) {
+
+	var match func(element *Element) bool
+
+	switch using {
+
+	case "css selector":
+		return findCSS(roots, value)
+
+	case "tag name":
+		match = func(element *Element) bool { return element.Tag == value }
+
+	case "link text":
+		match = func(element *Element) bool {
+			return element.Tag == "a" && strings.TrimSpace(element.VisibleText()) == value
+		}
+
+	case "partial link text":
+		match = func(element *Element) bool {
+			return element.Tag == "a" && strings.Contains(element.VisibleText(), value)
+		}
+
+	case "xpath":
+		return findXPath(roots, context, value)
+
+	default:
+		return nil, errInvalidSelector
+
+	}
+
+	return collect(roots, match), nil
+
+}
+
+func collect(roots []*Element, match func(element *Element) bool) []*Element {
+
+	elements := make([]*Element, 0)
+
+	for _, root := range roots {
+		root.walk(func(element *Element) {
+			if match(element) {
+				elements = append(elements, element)
+			}
+		})
+	}
+
+	return elements
+
+}
+
+var compoundPattern = regexp.MustCompile(`^([a-zA-Z][a-zA-Z0-9-]*|\*)?((?:#[\w-]+|\.[\w-]+|\[[\w-]+(?:[~^$*]?=(?:'[^']*'|"[^"]*"|[^\]]*))?\])*)$`)
+var partPattern = regexp.MustCompile(`#[\w-]+|\.[\w-]+|\[([\w-]+)(?:([~^$*]?=)('[^']*'|"[^"]*"|[^\]]*))?\]`)
+
+func findCSS(roots []*Element, selector string) ([]*Element, error) {
+
+	elements := make([]*Element, 0)
+	seen := make(map[*Element]bool)
+
+	for _, group := range strings.Split(selector, ",") {
+
+		compounds := strings.Fields(group)
+		if len(compounds) == 0 {
+			return nil, errInvalidSelector
+		}
+
+		matchers := make([]func(*Element) bool, len(compounds))
+		for i, compound := range compounds {
+			matcher, err := compileCompound(compound)
+			if err != nil {
+				return nil, err
+			}
+			matchers[i] = matcher
+		}
+
+		for _, element := range collect(roots, matchers[len(matchers)-1]) {
+			if !seen[element] && matchAncestors(element, matchers[:len(matchers)-1]) {
+				seen[element] = true
+				elements = append(elements, element)
+			}
+		}
+
+	}
+
+	return elements, nil
+
+}
+
+//matchAncestors reports whether the ancestors of element match matchers from right to left
+func matchAncestors(element *Element, matchers []func(*Element) bool) bool {
+
+	if len(matchers) == 0 {
+		return true
+	}
+
+	for ancestor := element.parent; ancestor != nil; ancestor = ancestor.parent {
+		if matchers[len(matchers)-1](ancestor) && matchAncestors(ancestor, matchers[:len(matchers)-1]) {
+			return true
+		}
+	}
+
+	return false
+
+}
+
+func compileCompound(compound string) (func(*Element) bool, error) {
+
+	groups := compoundPattern.FindStringSubmatch(compound)
+	if groups == nil {
+		return nil, errInvalidSelector
+	}
+
+	tag := groups[1]
+	parts := partPattern.FindAllStringSubmatch(groups[2], -1)
+
+	return func(element *Element) bool {
+
+		if tag != "" && tag != "*" && element.Tag != tag {
+			return false
+		}
+
+		for _, part := range parts {
+
+			switch part[0][0] {
+
+			case '#':
+				if element.Attributes["id"] != part[0][1:] {
+					return false
+				}
+
+			case '.':
+				if !hasClass(element, part[0][1:]) {
+					return false
+				}
+
+			case '[':
+				attribute, ok := element.GetAttribute(part[1])
+				if !ok || (part[2] != "" && !matchAttribute(attribute, part[2], unquote(part[3]))) {
+					return false
+				}
+
+			}
+
+		}
+
+		return true
+
+	}, nil
+
+}
+
+func matchAttribute(attribute string, operator string, value string) bool {
+
+	switch operator {
+	case "=":
+		return attribute == value
+	case "~=":
+		for _, word := range strings.Fields(attribute) {
+			if word == value {
+				return true
+			}
+		}
+		return false
+	case "^=":
+		return strings.HasPrefix(attribute, value)
+	case "$=":
+		return strings.HasSuffix(attribute, value)
+	case "*=":
+		return strings.Contains(attribute, value)
+	}
+
+	return false
+
+}
+
+func hasClass(element *Element, class string) bool {
+	for _, name := range strings.Fields(element.Attributes["class"]) {
+		if name == class {
+			return true
+		}
+	}
+	return false
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 && (value[0] == '\'' || value[0] == '"') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+var xpathPattern = regexp.MustCompile(`^\.?//([a-zA-Z][a-zA-Z0-9-]*|\*)(?:\[(?:@([\w-]+)|(text\(\)))\s*=\s*('[^']*'|"[^"]*")\])?$`)
+var ancestorPattern = regexp.MustCompile(`^\./ancestor-or-self::([a-zA-Z][a-zA-Z0-9-]*|\*)$`)
+
+func findXPath(roots []*Element, context *Element, expression string) ([]*Element, error) {
+
+	if groups := ancestorPattern.FindStringSubmatch(expression); groups != nil {
+
+		elements := make([]*Element, 0)
+		for ancestor := context; ancestor != nil; ancestor = ancestor.parent {
+			if groups[1] == "*" || ancestor.Tag == groups[1] {
+				elements = append([]*Element{ancestor}, elements...)
+			}
+		}
+
+		return elements, nil
+
+	}
+
+	groups := xpathPattern.FindStringSubmatch(expression)
+	if groups == nil {
+		return nil, errInvalidSelector
+	}
+
+	tag, attribute, text, value := groups[1], groups[2], groups[3], unquote(groups[4])
+
+	return collect(roots, func(element *Element) bool {
+
+		if tag != "*" && element.Tag != tag {
+			return false
+		}
+
+		if attribute != "" {
+			actual, ok := element.GetAttribute(attribute)
+			return ok && actual == value
+		}
+
+		if text != "" {
+			return strings.TrimSpace(element.VisibleText()) == value
+		}
+
+		return true
+
+	}), nil
+
+}
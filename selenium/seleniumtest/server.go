@@ -0,0 +1,969 @@
+package seleniumtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+
+	"../../selenium"
+)
+
+//ScriptHandler evaluates the scripts sent with "Execute Script" and "Execute Async Script".
+//Element references in args are decoded to *Element and *Element values in the result are encoded as references.
+//Handlers are called without the lock of the server, so they may call its methods, and should change the session through Do.
+type ScriptHandler func(session *Session, script string, args []interface{}) (interface{}, error)
+
+//Server is an in-process fake remote end speaking the W3C WebDriver protocol over HTTP.
+//It serves the pages added with AddPage from an in-memory DOM, so drivers created with
+//selenium.NewRemote(server.URL, caps) can be exercised without a browser.
+type Server struct {
+	*httptest.Server
+
+	mutex    sync.Mutex
+	pages    map[string]*Page
+	sessions map[string]*Session
+	scripts  ScriptHandler
+	nextID   int
+}
+
+//NewServer starts and returns a fake remote end. The caller should call Close when finished.
+func NewServer() *Server {
+
+	server := &Server{pages: make(map[string]*Page), sessions: make(map[string]*Session)}
+	server.Server = httptest.NewServer(server)
+
+	return server
+
+}
+
+//AddPage serves page when rawURL is navigated to
+func (server *Server) AddPage(rawURL string, page *Page) {
+
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	server.pages[rawURL] = page
+
+}
+
+//HandleScript sets the handler evaluating scripts. Without a handler scripts return null.
+func (server *Server) HandleScript(handler ScriptHandler) {
+
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	server.scripts = handler
+
+}
+
+//Session returns the session with the given ID, or nil
+func (server *Server) Session(id string) *Session {
+
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	return server.sessions[id]
+
+}
+
+//Sessions returns the IDs of the live sessions
+func (server *Server) Sessions() []string {
+
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	ids := make([]string, 0, len(server.sessions))
+	for id := range server.sessions {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	return ids
+
+}
+
+//Do runs fn with exclusive access to the session state, e.g. to inspect or change it between commands
+func (server *Server) Do(id string, fn func(session *Session)) {
+
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	if session, ok := server.sessions[id]; ok {
+		fn(session)
+	}
+
+}
+
+func (server *Server) page(rawURL string) (*Page, bool) {
+	page, ok := server.pages[rawURL]
+	return page, ok
+}
+
+//remoteError is a W3C error sent back to the local end
+type remoteError struct {
+	code    selenium.ErrorCode
+	message string
+	data    map[string]interface{}
+}
+
+func (e *remoteError) Error() string {
+	return fmt.Sprintf("%s: %s", e.code, e.message)
+}
+
+func newError(code selenium.ErrorCode, format string, args ...interface{}) *remoteError {
+	return &remoteError{code: code, message: fmt.Sprintf(format, args...)}
+}
+
+var errorStatus = map[selenium.ErrorCode]int{
+	selenium.ElementClickIntercepted: 400,
+	selenium.ElementNotInteractable:  400,
+	selenium.InsecureCertificate:     400,
+	selenium.InvalidArgument:         400,
+	selenium.InvalidCookieDomain:     400,
+	selenium.InvalidElementState:     400,
+	selenium.InvalidSelector:         400,
+	selenium.InvalidSessionID:        404,
+	selenium.JavascriptError:         500,
+	selenium.MoveTargetOutOfBounds:   500,
+	selenium.NoSuchAlert:             404,
+	selenium.NoSuchCookie:            404,
+	selenium.NoSuchElement:           404,
+	selenium.NoSuchFrame:             404,
+	selenium.NoSuchWindow:            404,
+	selenium.NoSuchShadowRoot:        404,
+	selenium.ScriptTimeout:           500,
+	selenium.SessionNotCreated:       500,
+	selenium.StaleElementReference:   404,
+	selenium.DetachedShadowRoot:      404,
+	selenium.Timeout:                 500,
+	selenium.UnableToSetCookie:       500,
+	selenium.UnableToCaptureScreen:   500,
+	selenium.UnexpectedAlertOpen:     500,
+	selenium.UnknownCommand:          404,
+	selenium.UnknownError:            500,
+	selenium.UnknownMethod:           405,
+	selenium.UnsupportedOperation:    500,
+}
+
+//ServeHTTP implements http.Handler
+func (server *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	payload := make(map[string]interface{})
+
+	if r.Method == http.MethodPost && r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			server.reply(w, nil, newError(selenium.InvalidArgument, "could not decode payload: %v", err))
+			return
+		}
+	}
+
+	server.mutex.Lock()
+	value, err := server.route(r.Method, strings.Trim(r.URL.Path, "/"), payload)
+	server.mutex.Unlock()
+
+	server.reply(w, value, err)
+
+}
+
+func (server *Server) reply(w http.ResponseWriter, value interface{}, err error) {
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	if err != nil {
+
+		remote, ok := err.(*remoteError)
+		if !ok {
+			remote = newError(selenium.UnknownError, "%v", err)
+		}
+
+		status, ok := errorStatus[remote.code]
+		if !ok {
+			status = 500
+		}
+
+		body := map[string]interface{}{"error": remote.code, "message": remote.message, "stacktrace": ""}
+		if remote.data != nil {
+			body["data"] = remote.data
+		}
+
+		w.WriteHeader(status)
+		json.NewEncoder(w).Encode(map[string]interface{}{"value": body})
+		return
+
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{"value": value})
+
+}
+
+func (server *Server) route(method string, path string, payload map[string]interface{}) (interface{}, error) {
+
+	segments := strings.Split(path, "/")
+
+	switch {
+
+	case method == http.MethodGet && path == "status":
+		return map[string]interface{}{"ready": true, "message": "seleniumtest remote end ready"}, nil
+
+	case method == http.MethodPost && path == "session":
+		return server.newSession(payload)
+
+	case len(segments) >= 2 && segments[0] == "session":
+
+		session, ok := server.sessions[segments[1]]
+		if !ok {
+			return nil, newError(selenium.InvalidSessionID, "no active session with ID %s", segments[1])
+		}
+
+		command := segments[2:]
+
+		if len(command) == 0 && method == http.MethodDelete {
+			delete(server.sessions, session.ID)
+			return nil, nil
+		}
+
+		if err := server.handlePrompt(session, command); err != nil {
+			return nil, err
+		}
+
+		return server.sessionCommand(session, method, command, payload)
+
+	}
+
+	return nil, newError(selenium.UnknownCommand, "unknown command: %s /%s", method, path)
+
+}
+
+func (server *Server) newSession(payload map[string]interface{}) (interface{}, error) {
+
+	requested := make(map[string]interface{})
+
+	//accept W3C, legacy and the bare alwaysMatch payloads
+	if capabilities, ok := payload["capabilities"].(map[string]interface{}); ok {
+		if alwaysMatch, ok := capabilities["alwaysMatch"].(map[string]interface{}); ok {
+			mergeInto(requested, alwaysMatch)
+		}
+		if firstMatch, ok := capabilities["firstMatch"].([]interface{}); ok && len(firstMatch) > 0 {
+			if first, ok := firstMatch[0].(map[string]interface{}); ok {
+				mergeInto(requested, first)
+			}
+		}
+	} else if alwaysMatch, ok := payload["alwaysMatch"].(map[string]interface{}); ok {
+		mergeInto(requested, alwaysMatch)
+	} else if desired, ok := payload["desiredCapabilities"].(map[string]interface{}); ok {
+		mergeInto(requested, desired)
+	}
+
+	capabilities := map[string]interface{}{
+		"browserName":         "seleniumtest",
+		"browserVersion":      "1.0",
+		"platformName":        "any",
+		"acceptInsecureCerts": false,
+		"setWindowRect":       true,
+		"pageLoadStrategy":    "normal",
+	}
+	mergeInto(capabilities, requested)
+
+	server.nextID++
+	id := fmt.Sprintf("session-%d", server.nextID)
+
+	session := newSession(server, id, capabilities)
+	capabilities["unhandledPromptBehavior"] = session.UnhandledPromptBehavior
+	capabilities["timeouts"] = timeoutsValue(session.Timeouts)
+
+	server.sessions[id] = session
+
+	return map[string]interface{}{"sessionId": id, "capabilities": capabilities}, nil
+
+}
+
+//handlePrompt applies the session's user prompt handler when a command other than the alert commands is sent while a prompt is open
+func (server *Server) handlePrompt(session *Session, command []string) error {
+
+	if session.alert == nil || (len(command) > 0 && command[0] == "alert") {
+		return nil
+	}
+
+	alert := session.alert
+
+	switch session.UnhandledPromptBehavior {
+	case selenium.AcceptPrompt:
+		session.closeAlert(true)
+		return nil
+	case selenium.DismissPrompt:
+		session.closeAlert(false)
+		return nil
+	case selenium.AcceptAndNotifyPrompt:
+		session.closeAlert(true)
+	case selenium.IgnorePrompt:
+	default:
+		session.closeAlert(false)
+	}
+
+	return &remoteError{
+		code:    selenium.UnexpectedAlertOpen,
+		message: fmt.Sprintf("unexpected alert open: {Alert text : %s}", alert.Text),
+		data:    map[string]interface{}{"text": alert.Text},
+	}
+
+}
+
+func (server *Server) sessionCommand(session *Session, method string, command []string, payload map[string]interface{}) (interface{}, error) {
+
+	route := method + " " + strings.Join(command, "/")
+
+	//commands addressing an element or a shadow root
+	if len(command) >= 3 && (command[0] == "element" || command[0] == "shadow") && command[1] != "active" {
+		return server.elementCommand(session, method, command, payload)
+	}
+
+	switch route {
+
+	case "GET timeouts":
+		return timeoutsValue(session.Timeouts), nil
+
+	case "POST timeouts":
+		for name, value := range payload {
+			ms, ok := value.(float64)
+			if !ok {
+				return nil, newError(selenium.InvalidArgument, "%s timeout must be a number", name)
+			}
+			switch name {
+			case "script":
+				session.Timeouts.Script = int(ms)
+			case "pageLoad":
+				session.Timeouts.PageLoad = int(ms)
+			case "implicit":
+				session.Timeouts.Implicit = int(ms)
+			}
+		}
+		return nil, nil
+
+	case "POST url":
+		rawURL, ok := payload["url"].(string)
+		if !ok {
+			return nil, newError(selenium.InvalidArgument, "url must be a string")
+		}
+		if _, err := server.window(session); err != nil {
+			return nil, err
+		}
+		session.Navigate(rawURL)
+		return nil, nil
+
+	case "GET url":
+		window, err := server.window(session)
+		if err != nil {
+			return nil, err
+		}
+		return window.URL, nil
+
+	case "POST back", "POST forward", "POST refresh":
+		window, err := server.window(session)
+		if err != nil {
+			return nil, err
+		}
+		switch command[0] {
+		case "back":
+			if window.position > 0 {
+				window.position--
+			}
+		case "forward":
+			if window.position < len(window.history)-1 {
+				window.position++
+			}
+		}
+		session.load(window, window.history[window.position])
+		return nil, nil
+
+	case "GET title":
+		window, err := server.window(session)
+		if err != nil {
+			return nil, err
+		}
+		return window.Title, nil
+
+	case "GET window":
+		window, err := server.window(session)
+		if err != nil {
+			return nil, err
+		}
+		return window.Handle, nil
+
+	case "DELETE window":
+		if _, err := server.window(session); err != nil {
+			return nil, err
+		}
+		session.closeWindow(session.current)
+		if len(session.handles) == 0 {
+			delete(server.sessions, session.ID)
+		}
+		return session.handles, nil
+
+	case "POST window":
+		handle, _ := payload["handle"].(string)
+		if _, ok := session.windows[handle]; !ok {
+			return nil, newError(selenium.NoSuchWindow, "no such window: %s", handle)
+		}
+		session.current = handle
+		return nil, nil
+
+	case "GET window/handles":
+		return session.handles, nil
+
+	case "POST window/new":
+		handle := session.NewWindow("about:blank")
+		return map[string]interface{}{"handle": handle, "type": "tab"}, nil
+
+	case "POST frame":
+		if _, err := server.window(session); err != nil {
+			return nil, err
+		}
+		if id, ok := payload["id"]; ok && id != nil {
+			return nil, newError(selenium.NoSuchFrame, "the fake remote end has no frames")
+		}
+		return nil, nil
+
+	case "POST frame/parent":
+		_, err := server.window(session)
+		return nil, err
+
+	case "GET window/rect":
+		window, err := server.window(session)
+		if err != nil {
+			return nil, err
+		}
+		return rectValue(window.Rect), nil
+
+	case "POST window/rect":
+		window, err := server.window(session)
+		if err != nil {
+			return nil, err
+		}
+		for name, value := range payload {
+			number, ok := value.(float64)
+			if !ok {
+				continue
+			}
+			switch name {
+			case "x":
+				window.Rect.X = int(number)
+			case "y":
+				window.Rect.Y = int(number)
+			case "width":
+				window.Rect.Width = int(number)
+			case "height":
+				window.Rect.Height = int(number)
+			}
+		}
+		window.State = "normal"
+		return rectValue(window.Rect), nil
+
+	case "POST window/maximize", "POST window/fullscreen":
+		window, err := server.window(session)
+		if err != nil {
+			return nil, err
+		}
+		window.Rect = selenium.Rect{X: 0, Y: 0, Width: 1920, Height: 1080}
+		window.State = command[1] + "d"
+		return rectValue(window.Rect), nil
+
+	case "POST window/minimize":
+		window, err := server.window(session)
+		if err != nil {
+			return nil, err
+		}
+		window.State = "minimized"
+		return rectValue(window.Rect), nil
+
+	case "POST element", "POST elements":
+		window, err := server.window(session)
+		if err != nil {
+			return nil, err
+		}
+		return server.find(command[0] == "elements", window.Document.Children, window.Document, payload)
+
+	case "GET element/active":
+		window, err := server.window(session)
+		if err != nil {
+			return nil, err
+		}
+		return reference(window.Active), nil
+
+	case "GET source":
+		window, err := server.window(session)
+		if err != nil {
+			return nil, err
+		}
+		builder := new(strings.Builder)
+		window.Document.html(builder)
+		return builder.String(), nil
+
+	case "POST execute/sync", "POST execute/async":
+		if _, err := server.window(session); err != nil {
+			return nil, err
+		}
+		return server.execute(session, payload)
+
+	case "GET cookie":
+		return session.Cookies, nil
+
+	case "POST cookie":
+		data, err := json.Marshal(payload["cookie"])
+		if err != nil {
+			return nil, newError(selenium.InvalidArgument, "%v", err)
+		}
+		cookie := new(selenium.Cookie)
+		if err := json.Unmarshal(data, cookie); err != nil || cookie.Name == "" {
+			return nil, newError(selenium.InvalidArgument, "invalid cookie")
+		}
+		if cookie.Domain == "" {
+			cookie.Domain = session.cookieDomain()
+		}
+		if cookie.Path == "" {
+			cookie.Path = "/"
+		}
+		if i := session.findCookie(cookie.Name); i >= 0 {
+			session.Cookies[i] = cookie
+		} else {
+			session.Cookies = append(session.Cookies, cookie)
+		}
+		return nil, nil
+
+	case "DELETE cookie":
+		session.Cookies = make([]*selenium.Cookie, 0)
+		return nil, nil
+
+	case "POST actions":
+		session.Actions = append(session.Actions, payload["actions"])
+		return nil, nil
+
+	case "DELETE actions":
+		return nil, nil
+
+	case "POST alert/dismiss", "POST alert/accept":
+		if session.alert == nil {
+			return nil, newError(selenium.NoSuchAlert, "no such alert")
+		}
+		session.closeAlert(command[1] == "accept")
+		return nil, nil
+
+	case "GET alert/text":
+		if session.alert == nil {
+			return nil, newError(selenium.NoSuchAlert, "no such alert")
+		}
+		return session.alert.Text, nil
+
+	case "POST alert/text":
+		if session.alert == nil {
+			return nil, newError(selenium.NoSuchAlert, "no such alert")
+		}
+		if session.alert.Type != "prompt" {
+			return nil, newError(selenium.ElementNotInteractable, "user prompt is not a window.prompt()")
+		}
+		text, ok := payload["text"].(string)
+		if !ok {
+			return nil, newError(selenium.InvalidArgument, "text must be a string")
+		}
+		session.alert.Value = text
+		return nil, nil
+
+	case "GET screenshot":
+		window, err := server.window(session)
+		if err != nil {
+			return nil, err
+		}
+		return screenshot(window.Rect.Width, window.Rect.Height)
+
+	case "POST print":
+		if _, err := server.window(session); err != nil {
+			return nil, err
+		}
+		return pdf, nil
+
+	}
+
+	if len(command) == 2 && command[0] == "cookie" {
+
+		i := session.findCookie(command[1])
+
+		switch method {
+		case http.MethodGet:
+			if i < 0 {
+				return nil, newError(selenium.NoSuchCookie, "no such cookie: %s", command[1])
+			}
+			return session.Cookies[i], nil
+		case http.MethodDelete:
+			if i >= 0 {
+				session.Cookies = append(session.Cookies[:i], session.Cookies[i+1:]...)
+			}
+			return nil, nil
+		}
+
+	}
+
+	return nil, newError(selenium.UnknownCommand, "unknown command: %s", route)
+
+}
+
+func (server *Server) window(session *Session) (*Window, error) {
+
+	window := session.Window()
+	if window == nil {
+		return nil, newError(selenium.NoSuchWindow, "current browsing context is no longer open")
+	}
+
+	return window, nil
+
+}
+
+func (server *Server) find(all bool, roots []*Element, context *Element, payload map[string]interface{}) (interface{}, error) {
+
+	using, _ := payload["using"].(string)
+	value, _ := payload["value"].(string)
+
+	elements, err := findAll(roots, context, using, value)
+	if err != nil {
+		return nil, newError(selenium.InvalidSelector, "invalid selector: %s %q", using, value)
+	}
+
+	if all {
+		references := make([]interface{}, len(elements))
+		for i, element := range elements {
+			references[i] = reference(element)
+		}
+		return references, nil
+	}
+
+	if len(elements) == 0 {
+		return nil, newError(selenium.NoSuchElement, "no such element: Unable to locate element: {\"method\":%q,\"selector\":%q}", using, value)
+	}
+
+	return reference(elements[0]), nil
+
+}
+
+func (server *Server) lookup(session *Session, kind string, id string) (*Element, error) {
+
+	if kind == "shadow" {
+		if host, ok := session.shadows[id]; ok {
+			return host, nil
+		}
+		if session.stale[id] {
+			return nil, newError(selenium.DetachedShadowRoot, "detached shadow root: %s", id)
+		}
+		return nil, newError(selenium.NoSuchShadowRoot, "no such shadow root: %s", id)
+	}
+
+	if element, ok := session.elements[id]; ok {
+		return element, nil
+	}
+
+	if session.stale[id] {
+		return nil, newError(selenium.StaleElementReference, "stale element reference: element %s is not attached to the page document", id)
+	}
+
+	return nil, newError(selenium.NoSuchElement, "no such element: %s", id)
+
+}
+
+func (server *Server) elementCommand(session *Session, method string, command []string, payload map[string]interface{}) (interface{}, error) {
+
+	if _, err := server.window(session); err != nil {
+		return nil, err
+	}
+
+	element, err := server.lookup(session, command[0], command[1])
+	if err != nil {
+		return nil, err
+	}
+
+	route := method + " " + command[0] + "/" + strings.Join(command[2:], "/")
+
+	switch route {
+
+	case "POST shadow/element", "POST shadow/elements":
+		return server.find(command[2] == "elements", element.Shadow, element, payload)
+
+	case "POST element/element", "POST element/elements":
+		return server.find(command[2] == "elements", element.Children, element, payload)
+
+	case "GET element/shadow":
+		if element.Shadow == nil {
+			return nil, newError(selenium.NoSuchShadowRoot, "element %s has no shadow root", element.id)
+		}
+		return map[string]interface{}{selenium.ShadowRootIdentifier: element.shadowID}, nil
+
+	case "GET element/selected":
+		return element.Selected, nil
+
+	case "GET element/enabled":
+		return !element.Disabled, nil
+
+	case "GET element/displayed":
+		return !element.isHidden(), nil
+
+	case "GET element/text":
+		return element.VisibleText(), nil
+
+	case "GET element/name":
+		return element.Tag, nil
+
+	case "GET element/rect":
+		return rectValue(element.rect()), nil
+
+	case "GET element/screenshot":
+		rect := element.rect()
+		return screenshot(rect.Width, rect.Height)
+
+	case "POST element/click":
+		if element.isHidden() {
+			return nil, newError(selenium.ElementNotInteractable, "element not interactable")
+		}
+		server.click(session, element)
+		return nil, nil
+
+	case "POST element/clear":
+		if element.Disabled {
+			return nil, newError(selenium.InvalidElementState, "invalid element state: element is disabled")
+		}
+		element.Value = ""
+		return nil, nil
+
+	case "POST element/value":
+		if element.isHidden() {
+			return nil, newError(selenium.ElementNotInteractable, "element not interactable")
+		}
+		text, ok := payload["text"].(string)
+		if !ok {
+			//legacy local ends send the keys as an array of characters
+			chars, _ := payload["value"].([]interface{})
+			for _, c := range chars {
+				if str, ok := c.(string); ok {
+					text += str
+				}
+			}
+		}
+		element.Value += typedText(text)
+		if window := session.Window(); window != nil {
+			window.Active = element
+		}
+		return nil, nil
+
+	}
+
+	if len(command) == 4 && command[0] == "element" && method == http.MethodGet {
+
+		switch command[2] {
+		case "attribute":
+			if value, ok := element.GetAttribute(command[3]); ok {
+				return value, nil
+			}
+			return nil, nil
+		case "property":
+			return element.GetProperty(command[3]), nil
+		case "css":
+			return element.CSS[command[3]], nil
+		}
+
+	}
+
+	return nil, newError(selenium.UnknownCommand, "unknown command: %s", route)
+
+}
+
+func (server *Server) click(session *Session, element *Element) {
+
+	if window := session.Window(); window != nil {
+		window.Active = element
+	}
+
+	if element.Tag == "option" || (element.Tag == "input" && (element.Attributes["type"] == "checkbox" || element.Attributes["type"] == "radio")) {
+		element.Selected = !element.Selected || element.Attributes["type"] == "radio"
+	}
+
+	if element.OnClick != nil {
+		element.OnClick(session, element)
+	}
+
+	if element.Tag == "a" && element.Attributes["href"] != "" {
+		session.Navigate(element.Attributes["href"])
+		return
+	}
+
+	if (element.Tag == "button" || element.Tag == "input") && element.Attributes["type"] == "submit" {
+		if form := closest(element, "form"); form != nil {
+			submit(session, form)
+		}
+	}
+
+}
+
+func (server *Server) execute(session *Session, payload map[string]interface{}) (interface{}, error) {
+
+	script, _ := payload["script"].(string)
+	rawArgs, _ := payload["args"].([]interface{})
+
+	args := make([]interface{}, len(rawArgs))
+	for i, arg := range rawArgs {
+		decoded, err := server.decode(session, arg)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = decoded
+	}
+
+	//scripts issued by the selenium package itself
+	switch strings.TrimSpace(script) {
+
+	case "arguments[0].submit()":
+		if form, ok := argument(args, 0).(*Element); ok {
+			submit(session, form)
+			return nil, nil
+		}
+
+	case "return arguments[0].getBoundingClientRect()":
+		if element, ok := argument(args, 0).(*Element); ok {
+			return rectValue(element.rect()), nil
+		}
+
+	}
+
+	scripts := server.scripts
+	if scripts == nil {
+		return nil, nil
+	}
+
+	//the handler runs without the lock, so it may call back into the server, e.g. with Do
+	server.mutex.Unlock()
+	result, err := scripts(session, script, args)
+	server.mutex.Lock()
+
+	if err != nil {
+		if remote, ok := err.(*remoteError); ok {
+			return nil, remote
+		}
+		return nil, newError(selenium.JavascriptError, "javascript error: %v", err)
+	}
+
+	return encode(result), nil
+
+}
+
+func (server *Server) decode(session *Session, value interface{}) (interface{}, error) {
+
+	switch arg := value.(type) {
+
+	case map[string]interface{}:
+
+		if id, ok := arg[selenium.WebElementIdentifier].(string); ok && len(arg) == 1 {
+			return server.lookup(session, "element", id)
+		}
+
+		if id, ok := arg[selenium.ShadowRootIdentifier].(string); ok && len(arg) == 1 {
+			return server.lookup(session, "shadow", id)
+		}
+
+		decoded := make(map[string]interface{}, len(arg))
+		for key, item := range arg {
+			d, err := server.decode(session, item)
+			if err != nil {
+				return nil, err
+			}
+			decoded[key] = d
+		}
+		return decoded, nil
+
+	case []interface{}:
+
+		decoded := make([]interface{}, len(arg))
+		for i, item := range arg {
+			d, err := server.decode(session, item)
+			if err != nil {
+				return nil, err
+			}
+			decoded[i] = d
+		}
+		return decoded, nil
+
+	}
+
+	return value, nil
+
+}
+
+func encode(value interface{}) interface{} {
+
+	switch result := value.(type) {
+
+	case *Element:
+		return reference(result)
+
+	case []*Element:
+		encoded := make([]interface{}, len(result))
+		for i, element := range result {
+			encoded[i] = reference(element)
+		}
+		return encoded
+
+	case map[string]interface{}:
+		encoded := make(map[string]interface{}, len(result))
+		for key, item := range result {
+			encoded[key] = encode(item)
+		}
+		return encoded
+
+	case []interface{}:
+		encoded := make([]interface{}, len(result))
+		for i, item := range result {
+			encoded[i] = encode(item)
+		}
+		return encoded
+
+	}
+
+	return value
+
+}
+
+func reference(element *Element) map[string]interface{} {
+	return map[string]interface{}{selenium.WebElementIdentifier: element.id}
+}
+
+func argument(args []interface{}, i int) interface{} {
+	if i < len(args) {
+		return args[i]
+	}
+	return nil
+}
+
+func closest(element *Element, tag string) *Element {
+	for ancestor := element; ancestor != nil; ancestor = ancestor.parent {
+		if ancestor.Tag == tag {
+			return ancestor
+		}
+	}
+	return nil
+}
+
+func submit(session *Session, form *Element) {
+
+	if form.OnSubmit != nil {
+		form.OnSubmit(session, form)
+		return
+	}
+
+	if action := form.Attributes["action"]; action != "" {
+		session.Navigate(action)
+	}
+
+}
+
+func mergeInto(dst map[string]interface{}, src map[string]interface{}) {
+	for key, value := range src {
+		dst[key] = value
+	}
+}
@@ -0,0 +1,216 @@
+package seleniumtest
+
+import (
+	"errors"
+	"testing"
+
+	"../../selenium"
+	"../../selenium/by"
+	"github.com/stretchr/testify/require"
+)
+
+func newDriver(t *testing.T, server *Server) selenium.WebDriver {
+
+	wd := selenium.NewRemote(server.URL, selenium.NewCapabilities())
+
+	_, err := wd.NewSession()
+	require.NoErrorf(t, err, "Session should be created by the fake remote end.")
+
+	return wd
+
+}
+
+func TestServerNavigation(t *testing.T) {
+
+	server := NewServer()
+	defer server.Close()
+
+	server.AddPage("http://example.test/", NewPage("Home",
+		NewElement("a").WithAttribute("href", "http://example.test/next").WithText("Next page"),
+	))
+	server.AddPage("http://example.test/next", NewPage("Next"))
+
+	wd := newDriver(t, server)
+
+	require.NoErrorf(t, wd.Navigate("http://example.test/"), "Navigate should succeed.")
+
+	title, err := wd.GetTitle()
+	require.NoErrorf(t, err, "GetTitle should succeed.")
+	require.Equalf(t, "Home", title, "Title should be the one of the served page.")
+
+	link, err := wd.FindElement(by.LinkText("Next page"))
+	require.NoErrorf(t, err, "Link should be found by its text.")
+	require.NoErrorf(t, link.Click(), "Link should be clickable.")
+
+	url, err := wd.GetCurrentURL()
+	require.NoErrorf(t, err, "GetCurrentURL should succeed.")
+	require.Equalf(t, "http://example.test/next", url, "Clicking the link should follow its href.")
+
+	require.NoErrorf(t, wd.Back(), "Back should succeed.")
+
+	title, err = wd.GetTitle()
+	require.NoErrorf(t, err, "GetTitle should succeed.")
+	require.Equalf(t, "Home", title, "Back should return to the previous page.")
+
+	err = wd.ElementClick(link)
+	require.Truef(t, errors.Is(err, selenium.StaleElementReference), "Elements of a replaced document should be stale.")
+
+	handles, err := wd.GetWindowHandles()
+	require.NoErrorf(t, err, "GetWindowHandles should succeed.")
+	require.Lenf(t, handles, 1, "Session should have a single window.")
+
+	require.NoErrorf(t, wd.DeleteSession(), "DeleteSession should succeed.")
+	require.Emptyf(t, server.Sessions(), "Deleted session should be removed.")
+
+}
+
+func TestServerElements(t *testing.T) {
+
+	server := NewServer()
+	defer server.Close()
+
+	server.AddPage("http://example.test/", NewPage("Form",
+		NewElement("form",
+			NewElement("input").WithAttribute("id", "name").WithAttribute("type", "text"),
+			NewElement("input").WithAttribute("class", "option").WithAttribute("type", "checkbox"),
+			NewElement("input").WithAttribute("class", "option").WithAttribute("type", "checkbox"),
+		),
+		NewElement("my-widget").WithShadowRoot(
+			NewElement("span").WithAttribute("class", "inner").WithText("shadow text"),
+		),
+	))
+
+	wd := newDriver(t, server)
+	require.NoErrorf(t, wd.Navigate("http://example.test/"), "Navigate should succeed.")
+
+	input, err := wd.FindElement(by.ID("name"))
+	require.NoErrorf(t, err, "Input should be found by ID.")
+	require.NoErrorf(t, wd.ElementSendKeys(input, "gopher"), "Keys should be sent to the input.")
+
+	value, err := wd.GetElementProperty(input, "value")
+	require.NoErrorf(t, err, "GetElementProperty should succeed.")
+	require.Equalf(t, "gopher", value, "Typed keys should update the value property.")
+
+	tag, err := wd.GetElementTagName(input)
+	require.NoErrorf(t, err, "GetElementTagName should succeed.")
+	require.Equalf(t, "input", tag, "Tag name should be returned.")
+
+	missing, err := wd.GetElementAttribute(input, "placeholder")
+	require.NoErrorf(t, err, "Missing attributes should not be an error.")
+	require.Equalf(t, "", missing, "Missing attributes should be empty.")
+
+	options, err := wd.FindElements(by.CSS("form input.option"))
+	require.NoErrorf(t, err, "FindElements should succeed.")
+	require.Lenf(t, options, 2, "Both checkboxes should be found.")
+
+	require.NoErrorf(t, wd.ElementClick(options[1]), "Checkbox should be clickable.")
+	selected, err := wd.IsElementSelected(options[1])
+	require.NoErrorf(t, err, "IsElementSelected should succeed.")
+	require.Truef(t, selected, "Clicking a checkbox should select it.")
+
+	_, err = wd.FindElement(by.ID("missing"))
+	require.Truef(t, errors.Is(err, selenium.NoSuchElement), "Unknown elements should not be found.")
+
+	widget, err := wd.FindElement(by.Tag("my-widget"))
+	require.NoErrorf(t, err, "Shadow host should be found.")
+
+	root, err := wd.GetElementShadowRoot(widget)
+	require.NoErrorf(t, err, "Shadow root should be returned.")
+
+	inner, err := wd.FindElementFromShadowRoot(root, by.CSS(".inner"))
+	require.NoErrorf(t, err, "Element should be found inside the shadow root.")
+
+	text, err := wd.GetElementText(inner)
+	require.NoErrorf(t, err, "GetElementText should succeed.")
+	require.Equalf(t, "shadow text", text, "Text of the shadow element should be returned.")
+
+	screenshot, err := wd.TakeElementScreenshot(inner)
+	require.NoErrorf(t, err, "TakeElementScreenshot should succeed.")
+	image, err := screenshot.Image()
+	require.NoErrorf(t, err, "Screenshot should be a PNG.")
+	require.Equalf(t, 100, image.Bounds().Dx(), "Screenshot should have the size of the element.")
+
+}
+
+func TestServerAlertsAndCookies(t *testing.T) {
+
+	server := NewServer()
+	defer server.Close()
+
+	accepted := false
+
+	server.AddPage("http://example.test/", NewPage("Alerts",
+		NewElement("button").WithAttribute("id", "confirm").WithOnClick(func(session *Session, element *Element) {
+			session.OpenAlert("confirm", "Are you sure?", func(session *Session, ok bool, value string) {
+				accepted = ok
+			})
+		}),
+	))
+
+	wd := newDriver(t, server)
+	require.NoErrorf(t, wd.Navigate("http://example.test/"), "Navigate should succeed.")
+
+	button, err := wd.FindElement(by.ID("confirm"))
+	require.NoErrorf(t, err, "Button should be found.")
+	require.NoErrorf(t, button.Click(), "Button should be clickable.")
+
+	text, err := wd.GetAlertText()
+	require.NoErrorf(t, err, "GetAlertText should succeed.")
+	require.Equalf(t, "Are you sure?", text, "Alert text should be returned.")
+
+	require.NoErrorf(t, wd.AcceptAlert(), "AcceptAlert should succeed.")
+	require.Truef(t, accepted, "Accepting the alert should run its callback.")
+
+	require.NoErrorf(t, button.Click(), "Button should be clickable.")
+
+	_, err = wd.GetTitle()
+	alertErr := new(selenium.UnexpectedAlertOpenError)
+	require.Truef(t, errors.As(err, &alertErr), "Commands should fail while a prompt is open.")
+	require.Equalf(t, "Are you sure?", alertErr.Text, "Error should carry the prompt text.")
+	require.Falsef(t, accepted, "Default prompt behavior should dismiss the prompt.")
+
+	require.NoErrorf(t, wd.AddCookie(&selenium.Cookie{Name: "token", Value: "secret"}), "AddCookie should succeed.")
+
+	cookie, err := wd.GetNamedCookie("token")
+	require.NoErrorf(t, err, "GetNamedCookie should succeed.")
+	require.Equalf(t, "example.test", cookie.Domain, "Cookie domain should default to the current host.")
+
+	require.NoErrorf(t, wd.DeleteCookie("token"), "DeleteCookie should succeed.")
+
+	_, err = wd.GetNamedCookie("token")
+	require.Truef(t, errors.Is(err, selenium.NoSuchCookie), "Deleted cookie should not be found.")
+
+	require.NoErrorf(t, wd.SetTimeouts(&selenium.Timeouts{Implicit: 500}), "SetTimeouts should succeed.")
+
+	timeouts, err := wd.GetTimeouts()
+	require.NoErrorf(t, err, "GetTimeouts should succeed.")
+	require.Equalf(t, 500, timeouts.Implicit, "Implicit timeout should be updated.")
+	require.Equalf(t, 30000, timeouts.Script, "Script timeout should keep its default.")
+
+}
+
+func TestServerScriptCallback(t *testing.T) {
+
+	server := NewServer()
+	defer server.Close()
+
+	//the handler reads the server state, which would deadlock if handlers were called with the lock held
+	server.HandleScript(func(session *Session, script string, args []interface{}) (interface{}, error) {
+		server.Do(session.ID, func(session *Session) {
+			session.UnhandledPromptBehavior = "ignore"
+		})
+		return len(server.Sessions()), nil
+	})
+
+	wd := newDriver(t, server)
+
+	value, err := wd.ExecuteScript("return 1")
+	require.NoErrorf(t, err, "Script should be evaluated.")
+	require.Equalf(t, float64(1), value, "Handler should reach the server.")
+
+	info := wd.(selenium.WebDriverInfo)
+	server.Do(info.GetSession().GetID(), func(session *Session) {
+		require.Equalf(t, "ignore", session.UnhandledPromptBehavior, "Session should be changed through Do.")
+	})
+
+}
@@ -0,0 +1,265 @@
+package seleniumtest
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"../../selenium"
+)
+
+//Session is the state of a WebDriver session held by the fake remote end
+type Session struct {
+	ID                      string
+	Capabilities            map[string]interface{}
+	Timeouts                selenium.Timeouts
+	Cookies                 []*selenium.Cookie
+	UnhandledPromptBehavior string
+	Actions                 []interface{}
+
+	server   *Server
+	windows  map[string]*Window
+	handles  []string
+	current  string
+	alert    *Alert
+	elements map[string]*Element
+	shadows  map[string]*Element
+	stale    map[string]bool
+	nextID   int
+}
+
+//Window is a top-level browsing context of a session
+type Window struct {
+	Handle   string
+	URL      string
+	Title    string
+	Document *Element
+	Rect     selenium.Rect
+	State    string
+	Active   *Element
+
+	history  []string
+	position int
+}
+
+//Alert is a user prompt opened by a scripted page
+type Alert struct {
+	Type    string
+	Text    string
+	Value   string
+	OnClose func(session *Session, accepted bool, value string)
+}
+
+func newSession(server *Server, id string, capabilities map[string]interface{}) *Session {
+
+	session := &Session{
+		ID:                      id,
+		Capabilities:            capabilities,
+		Timeouts:                selenium.Timeouts{Script: 30000, PageLoad: 300000, Implicit: 0},
+		Cookies:                 make([]*selenium.Cookie, 0),
+		UnhandledPromptBehavior: selenium.DismissAndNotifyPrompt,
+		Actions:                 make([]interface{}, 0),
+		server:                  server,
+		windows:                 make(map[string]*Window),
+		handles:                 make([]string, 0),
+		elements:                make(map[string]*Element),
+		shadows:                 make(map[string]*Element),
+		stale:                   make(map[string]bool),
+	}
+
+	if behavior, ok := capabilities["unhandledPromptBehavior"].(string); ok {
+		session.UnhandledPromptBehavior = behavior
+	}
+
+	session.current = session.NewWindow("about:blank")
+
+	return session
+
+}
+
+//NewWindow opens a new top-level browsing context at rawURL and returns its handle
+func (session *Session) NewWindow(rawURL string) string {
+
+	session.nextID++
+	handle := fmt.Sprintf("window-%d", session.nextID)
+
+	window := &Window{Handle: handle, Rect: selenium.Rect{X: 0, Y: 0, Width: 1280, Height: 800}, State: "normal"}
+	session.windows[handle] = window
+	session.handles = append(session.handles, handle)
+
+	session.load(window, rawURL)
+
+	window.history = []string{window.URL}
+	window.position = 0
+
+	return handle
+
+}
+
+//Window returns the current top-level browsing context, or nil if it was closed
+func (session *Session) Window() *Window {
+	return session.windows[session.current]
+}
+
+//Navigate loads rawURL in the current window
+func (session *Session) Navigate(rawURL string) {
+
+	window := session.Window()
+	if window == nil {
+		return
+	}
+
+	session.load(window, rawURL)
+
+	window.history = append(window.history[:window.position+1], window.URL)
+	window.position = len(window.history) - 1
+
+}
+
+//Alert returns the currently open user prompt, or nil
+func (session *Session) Alert() *Alert {
+	return session.alert
+}
+
+//OpenAlert opens a user prompt of kind "alert", "confirm" or "prompt". onClose, if not nil, runs when it is accepted or dismissed.
+func (session *Session) OpenAlert(kind string, text string, onClose func(session *Session, accepted bool, value string)) {
+	session.alert = &Alert{Type: kind, Text: text, OnClose: onClose}
+}
+
+//Element returns the element of the current documents with the given web element reference
+func (session *Session) Element(id string) *Element {
+	return session.elements[id]
+}
+
+func (session *Session) closeAlert(accepted bool) {
+
+	alert := session.alert
+	session.alert = nil
+
+	if alert != nil && alert.OnClose != nil {
+		alert.OnClose(session, accepted, alert.Value)
+	}
+
+}
+
+func (session *Session) load(window *Window, rawURL string) {
+
+	if window.Document != nil {
+		session.retire(window.Document)
+	}
+
+	window.URL = rawURL
+	window.Title = ""
+	window.Document = NewPage("").document()
+
+	if page, ok := session.server.page(rawURL); ok {
+		window.Title = page.Title
+		window.Document = page.document()
+	}
+
+	window.Document.walk(func(element *Element) {
+		session.register(element)
+	})
+
+	window.Active = body(window.Document)
+
+}
+
+func (session *Session) register(element *Element) {
+
+	session.nextID++
+	element.id = fmt.Sprintf("element-%d", session.nextID)
+	session.elements[element.id] = element
+
+	if element.Shadow != nil {
+		session.nextID++
+		element.shadowID = fmt.Sprintf("shadow-%d", session.nextID)
+		session.shadows[element.shadowID] = element
+		for _, child := range element.Shadow {
+			child.walk(func(element *Element) { session.register(element) })
+		}
+	}
+
+}
+
+func (session *Session) retire(document *Element) {
+
+	var retire func(element *Element)
+	retire = func(element *Element) {
+		delete(session.elements, element.id)
+		session.stale[element.id] = true
+		if element.Shadow != nil {
+			delete(session.shadows, element.shadowID)
+			session.stale[element.shadowID] = true
+			for _, child := range element.Shadow {
+				child.walk(retire)
+			}
+		}
+	}
+
+	document.walk(retire)
+
+}
+
+func (session *Session) closeWindow(handle string) {
+
+	window, ok := session.windows[handle]
+	if !ok {
+		return
+	}
+
+	session.retire(window.Document)
+	delete(session.windows, handle)
+
+	for i, h := range session.handles {
+		if h == handle {
+			session.handles = append(session.handles[:i], session.handles[i+1:]...)
+			break
+		}
+	}
+
+}
+
+func (session *Session) cookieDomain() string {
+
+	window := session.Window()
+	if window == nil {
+		return ""
+	}
+
+	parsed, err := url.Parse(window.URL)
+	if err != nil {
+		return ""
+	}
+
+	return parsed.Hostname()
+
+}
+
+func (session *Session) findCookie(name string) int {
+	for i, cookie := range session.Cookies {
+		if cookie.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func body(document *Element) *Element {
+	for _, child := range document.Children {
+		if child.Tag == "body" {
+			return child
+		}
+	}
+	return document
+}
+
+//typedText removes the WebDriver key codes, which live in the Unicode private use area, from text
+func typedText(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '\ue000' && r <= '\uf8ff' {
+			return -1
+		}
+		return r
+	}, text)
+}
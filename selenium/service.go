@@ -0,0 +1,427 @@
+package selenium
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+)
+
+//DefaultStartTimeout is the time a Service waits for its driver to become ready
+const DefaultStartTimeout = 20 * time.Second
+
+//DefaultStopTimeout is the time a Service waits for its driver to exit after SIGTERM before killing it
+const DefaultStopTimeout = 5 * time.Second
+
+//maxCapturedOutput is the amount of driver output kept to report early exits
+const maxCapturedOutput = 64 * 1024
+
+//ServiceExitError is returned by Service.Start when the driver exits before becoming ready
+type ServiceExitError struct {
+	Err    error
+	Output string
+}
+
+func (e *ServiceExitError) Error() string {
+
+	if e.Output == "" {
+		return fmt.Sprintf("driver exited before becoming ready: %v", e.Err)
+	}
+
+	return fmt.Sprintf("driver exited before becoming ready: %v\n%s", e.Err, e.Output)
+
+}
+
+func (e *ServiceExitError) Unwrap() error {
+	return e.Err
+}
+
+//Service manages a local driver server process such as chromedriver or geckodriver
+type Service struct {
+	path         string
+	args         []string
+	port         int
+	portArgs     func(port int) []string
+	env          []string
+	output       io.Writer
+	logFile      *os.File
+	startTimeout time.Duration
+	stopTimeout  time.Duration
+
+	mutex   sync.Mutex
+	cmd     *exec.Cmd
+	capture *outputBuffer
+	exited  chan struct{}
+	exitErr error
+}
+
+//NewService returns a service running the driver at path with args. When port is 0 a free port is picked on Start.
+func NewService(path string, port int, args ...string) *Service {
+	return &Service{
+		path:         path,
+		args:         args,
+		port:         port,
+		portArgs:     func(port int) []string { return []string{"--port=" + strconv.Itoa(port)} },
+		startTimeout: DefaultStartTimeout,
+		stopTimeout:  DefaultStopTimeout,
+	}
+}
+
+//SetPortArgs sets how the port is passed to the driver, "--port=<port>" by default
+func (service *Service) SetPortArgs(portArgs func(port int) []string) {
+	service.portArgs = portArgs
+}
+
+//SetEnv sets the environment of the driver process, in the form of os.Environ
+func (service *Service) SetEnv(env []string) {
+	service.env = env
+}
+
+//SetOutput streams the stdout and stderr of the driver to w
+func (service *Service) SetOutput(w io.Writer) {
+	service.output = w
+}
+
+//SetLogFile streams the stdout and stderr of the driver to the named file, which is created or appended to
+func (service *Service) SetLogFile(path string) error {
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+
+	service.logFile = file
+	service.output = file
+
+	return nil
+
+}
+
+//SetStartTimeout sets the overall time Start waits for the driver to become ready
+func (service *Service) SetStartTimeout(timeout time.Duration) {
+	service.startTimeout = timeout
+}
+
+//SetStopTimeout sets the time Stop waits for the driver to exit gracefully before killing it
+func (service *Service) SetStopTimeout(timeout time.Duration) {
+	service.stopTimeout = timeout
+}
+
+//GetPort returns the port the driver listens on
+func (service *Service) GetPort() int {
+	return service.port
+}
+
+//GetURL returns the URL of the driver
+func (service *Service) GetURL() string {
+	return fmt.Sprintf("http://127.0.0.1:%d", service.port)
+}
+
+//Output returns the most recent output of the driver
+func (service *Service) Output() string {
+
+	if service.capture == nil {
+		return ""
+	}
+
+	return service.capture.String()
+
+}
+
+//Start starts the driver and waits, with exponential backoff, until its status reports it ready
+func (service *Service) Start() error {
+
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	if service.cmd != nil {
+		return errors.New("service already started")
+	}
+
+	if service.port == 0 {
+		port, err := FreePort()
+		if err != nil {
+			return err
+		}
+		service.port = port
+	}
+
+	args := append(append(make([]string, 0), service.args...), service.portArgs(service.port)...)
+
+	cmd := exec.Command(service.path, args...)
+	cmd.Env = service.env
+
+	service.capture = newOutputBuffer(maxCapturedOutput)
+
+	var output io.Writer = service.capture
+	if service.output != nil {
+		output = io.MultiWriter(service.capture, service.output)
+	}
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	//run the driver in its own process group so the browsers it launches are stopped with it
+	setProcessGroup(cmd)
+
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	service.cmd = cmd
+	service.exited = make(chan struct{})
+
+	go func() {
+		service.exitErr = cmd.Wait()
+		close(service.exited)
+	}()
+
+	registerService(service)
+
+	err := service.waitReady()
+	if err != nil {
+		unregisterService(service)
+	}
+
+	return err
+
+}
+
+func (service *Service) waitReady() error {
+
+	ctx, cancel := context.WithTimeout(context.Background(), service.startTimeout)
+	defer cancel()
+
+	wd := NewRemote(service.GetURL(), NewCapabilities())
+
+	var lastErr error
+
+	for backoff := 50 * time.Millisecond; ; backoff *= 2 {
+
+		status, err := wd.WithContext(ctx).GetStatus()
+		if err == nil && status.IsReady() {
+			return nil
+		}
+
+		lastErr = err
+		if lastErr == nil {
+			lastErr = errors.New("driver is not ready: " + status.GetMessage())
+		}
+
+		if backoff > time.Second {
+			backoff = time.Second
+		}
+
+		select {
+		case <-service.exited:
+			return &ServiceExitError{Err: exitError(service.exitErr), Output: service.Output()}
+		case <-ctx.Done():
+			service.kill()
+			return fmt.Errorf("driver did not become ready within %v: %w", service.startTimeout, lastErr)
+		case <-time.After(backoff):
+		}
+
+	}
+
+}
+
+//Stop terminates the driver and its process group, first with SIGTERM then, after the stop timeout, with SIGKILL,
+//and waits for it to exit. It is safe to call Stop several times, or after Start failed.
+func (service *Service) Stop() error {
+
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	var err error
+
+	//the process is forgotten once stopped, as its process group id may be reused by another process
+	if service.cmd != nil {
+		unregisterService(service)
+		err = service.terminate()
+		service.cmd = nil
+	}
+
+	if service.logFile != nil {
+		service.logFile.Close()
+		service.logFile = nil
+	}
+
+	return err
+
+}
+
+func (service *Service) terminate() error {
+
+	select {
+	case <-service.exited:
+	default:
+		if err := terminateProcessGroup(service.cmd); err == nil {
+			select {
+			case <-service.exited:
+			case <-time.After(service.stopTimeout):
+			}
+		}
+	}
+
+	//kill whatever is left of the process group, e.g. browsers ignoring SIGTERM
+	service.kill()
+
+	return nil
+
+}
+
+func (service *Service) kill() {
+
+	killProcessGroup(service.cmd)
+	<-service.exited
+
+}
+
+func exitError(err error) error {
+
+	if err == nil {
+		return errors.New("exit status 0")
+	}
+
+	return err
+
+}
+
+var (
+	servicesMutex sync.Mutex
+	services      = make(map[*Service]bool)
+)
+
+func registerService(service *Service) {
+
+	servicesMutex.Lock()
+	defer servicesMutex.Unlock()
+
+	services[service] = true
+
+}
+
+func unregisterService(service *Service) {
+
+	servicesMutex.Lock()
+	defer servicesMutex.Unlock()
+
+	delete(services, service)
+
+}
+
+//StopAllServices stops every service started and not stopped yet
+func StopAllServices() {
+
+	servicesMutex.Lock()
+	live := make([]*Service, 0, len(services))
+	for service := range services {
+		live = append(live, service)
+	}
+	servicesMutex.Unlock()
+
+	var wg sync.WaitGroup
+	for _, service := range live {
+		wg.Add(1)
+		go func(service *Service) {
+			defer wg.Done()
+			service.Stop()
+		}(service)
+	}
+	wg.Wait()
+
+}
+
+//HandleInterrupt stops every live service when the program receives SIGINT or SIGTERM, then exits with status 130 or 143.
+//The returned function removes the handler.
+func HandleInterrupt() func() {
+
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+
+	signal.Notify(signals, interruptSignals...)
+
+	go func() {
+		select {
+		case sig := <-signals:
+			StopAllServices()
+			os.Exit(interruptStatus(sig))
+		case <-done:
+		}
+	}()
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+		})
+	}
+
+}
+
+//interruptStatus returns the conventional exit status of a program ended by sig, 128 plus the signal number
+func interruptStatus(sig os.Signal) int {
+
+	if number, ok := sig.(syscall.Signal); ok {
+		return 128 + int(number)
+	}
+
+	return 130
+
+}
+
+//FreePort returns a TCP port on the loopback interface that is free at the time of the call
+func FreePort() (int, error) {
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+
+	return listener.Addr().(*net.TCPAddr).Port, nil
+
+}
+
+//outputBuffer keeps the last bytes written to it
+type outputBuffer struct {
+	mutex sync.Mutex
+	data  []byte
+	size  int
+}
+
+func newOutputBuffer(size int) *outputBuffer {
+	return &outputBuffer{size: size}
+}
+
+func (buffer *outputBuffer) Write(p []byte) (int, error) {
+
+	buffer.mutex.Lock()
+	defer buffer.mutex.Unlock()
+
+	buffer.data = append(buffer.data, p...)
+	if len(buffer.data) > buffer.size {
+		buffer.data = buffer.data[len(buffer.data)-buffer.size:]
+	}
+
+	return len(p), nil
+
+}
+
+func (buffer *outputBuffer) String() string {
+
+	buffer.mutex.Lock()
+	defer buffer.mutex.Unlock()
+
+	return string(buffer.data)
+
+}
@@ -0,0 +1,121 @@
+package selenium
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+//TestServiceHelperProcess is not a real test: it is run as a fake driver by the service tests
+func TestServiceHelperProcess(t *testing.T) {
+
+	mode := os.Getenv("SELENIUM_SERVICE_HELPER")
+	if mode == "" {
+		return
+	}
+
+	fmt.Println("fake driver starting")
+
+	if mode == "exit" {
+		fmt.Println("unknown option")
+		os.Exit(3)
+	}
+
+	if mode == "ignore-term" {
+		signal.Ignore(syscall.SIGTERM)
+	}
+
+	port := ""
+	for _, arg := range os.Args {
+		if strings.HasPrefix(arg, "--port=") {
+			port = strings.TrimPrefix(arg, "--port=")
+		}
+	}
+
+	http.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"value": {"ready": true, "message": "ready"}}`))
+	})
+	http.ListenAndServe("127.0.0.1:"+port, nil)
+	os.Exit(0)
+
+}
+
+func newHelperService(mode string) *Service {
+
+	service := NewService(os.Args[0], 0, "-test.run=TestServiceHelperProcess", "--")
+	service.SetEnv(append(os.Environ(), "SELENIUM_SERVICE_HELPER="+mode))
+
+	return service
+
+}
+
+func TestService(t *testing.T) {
+
+	output := new(bytes.Buffer)
+
+	service := newHelperService("serve")
+	service.SetOutput(output)
+	service.SetStartTimeout(10 * time.Second)
+
+	err := service.Start()
+	require.NoErrorf(t, err, "Service should start and become ready.")
+	require.Truef(t, service.GetPort() != 0, "A free port should be picked.")
+
+	status, err := NewRemote(service.GetURL(), NewCapabilities()).GetStatus()
+	require.NoErrorf(t, err, "Driver should be reachable on the service URL.")
+	require.Truef(t, status.IsReady(), "Driver should be ready.")
+
+	require.NoErrorf(t, service.Stop(), "Service should stop.")
+	require.Containsf(t, output.String(), "fake driver starting", "Driver output should be streamed to the writer.")
+
+	require.NoErrorf(t, service.Stop(), "Stopping a stopped service should be a no-op.")
+	require.NoErrorf(t, service.Start(), "Stopped service should be started again.")
+	require.NoErrorf(t, service.Stop(), "Restarted service should stop.")
+
+}
+
+func TestServiceEarlyExit(t *testing.T) {
+
+	service := newHelperService("exit")
+	service.SetStartTimeout(10 * time.Second)
+
+	err := service.Start()
+
+	exitErr := new(ServiceExitError)
+	require.Truef(t, errors.As(err, &exitErr), "Early exit should be reported.")
+	require.Containsf(t, exitErr.Output, "unknown option", "Captured output should be reported.")
+	require.NoErrorf(t, service.Stop(), "Service should be stopped after a failed start.")
+
+}
+
+func TestServiceStopKillsStubbornDriver(t *testing.T) {
+
+	service := newHelperService("ignore-term")
+	service.SetStartTimeout(10 * time.Second)
+	service.SetStopTimeout(100 * time.Millisecond)
+
+	require.NoErrorf(t, service.Start(), "Service should start and become ready.")
+
+	StopAllServices()
+
+	_, err := NewRemote(service.GetURL(), NewCapabilities()).GetStatus()
+	require.Errorf(t, err, "Driver ignoring SIGTERM should be killed after the stop timeout.")
+	require.NoErrorf(t, service.Stop(), "Stopping a stopped service should be a no-op.")
+
+}
+
+func TestInterruptStatus(t *testing.T) {
+
+	require.Equalf(t, 130, interruptStatus(os.Interrupt), "SIGINT should exit with status 130.")
+	require.Equalf(t, 143, interruptStatus(syscall.SIGTERM), "SIGTERM should exit with status 143.")
+
+}
@@ -3,6 +3,7 @@ package selenium
 type session struct {
 	ID           string
 	Capabilities map[string]interface{}
+	Dialect      Dialect
 }
 
 func (session *session) GetID() string {
@@ -12,3 +13,7 @@ func (session *session) GetID() string {
 func (session *session) GetCapabilities() map[string]interface{} {
 	return session.Capabilities
 }
+
+func (session *session) GetDialect() Dialect {
+	return session.Dialect
+}
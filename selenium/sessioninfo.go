@@ -3,4 +3,5 @@ package selenium
 type SessionInfo interface {
 	GetID() string
 	GetCapabilities() map[string]interface{}
+	GetDialect() Dialect
 }
@@ -0,0 +1,37 @@
+package selenium
+
+import "./by"
+
+type shadowRoot struct {
+	id     string
+	value  string
+	driver WebDriver
+}
+
+func (s *shadowRoot) SetDriver(driver WebDriver) error {
+	s.driver = driver
+	return nil
+}
+
+/* Get WebDriver ID */
+func (s *shadowRoot) GetID() string { return s.id }
+
+/* Get WebDriver ID */
+func (s *shadowRoot) GetValue() string { return s.value }
+
+/* FindElement returns one WebElement within the shadow root found via Locator. */
+func (s *shadowRoot) FindElement(locator *by.Locator) (WebElement, error) {
+	return s.driver.FindElementFromShadowRoot(s, locator)
+}
+
+/* FindElements return list of elements within the shadow root found via Locator. */
+func (s *shadowRoot) FindElements(locator *by.Locator) ([]WebElement, error) {
+	return s.driver.FindElementsFromShadowRoot(s, locator)
+}
+
+//ShadowRoot provides an interface to the shadow DOM attached to a WebElement.
+//Remote ends do not support XPath locators within shadow roots.
+type ShadowRoot interface {
+	FindElement(locator *by.Locator) (WebElement, error)
+	FindElements(locator *by.Locator) ([]WebElement, error)
+}
@@ -0,0 +1,118 @@
+package selenium
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+//EndpointStats holds the latency statistics of one command route
+type EndpointStats struct {
+	Method  Method
+	Command string
+	Count   int
+	Errors  int
+	Total   time.Duration
+	Min     time.Duration
+	Max     time.Duration
+}
+
+//Mean returns the average duration of the command
+func (stats *EndpointStats) Mean() time.Duration {
+	if stats.Count == 0 {
+		return 0
+	}
+	return stats.Total / time.Duration(stats.Count)
+}
+
+//LatencyStats collects per-endpoint latency statistics of the commands passing through its middleware
+type LatencyStats struct {
+	mutex sync.Mutex
+	stats map[string]*EndpointStats
+}
+
+//NewLatencyStats returns an empty latency statistics collector
+func NewLatencyStats() *LatencyStats {
+	return &LatencyStats{stats: make(map[string]*EndpointStats)}
+}
+
+//Middleware returns the middleware recording the duration of every command
+func (latency *LatencyStats) Middleware() Middleware {
+
+	return func(next CommandExecutor) CommandExecutor {
+		return CommandExecutorFunc(func(ctx context.Context, method Method, endpoint string, data interface{}) (*Reply, error) {
+
+			start := time.Now()
+			reply, err := next.Execute(ctx, method, endpoint, data)
+
+			latency.record(method, CommandName(endpoint), time.Since(start), err != nil || reply.StatusCode != 200)
+
+			return reply, err
+
+		})
+	}
+
+}
+
+//Snapshot returns a copy of the statistics recorded so far, sorted by command
+func (latency *LatencyStats) Snapshot() []EndpointStats {
+
+	latency.mutex.Lock()
+	defer latency.mutex.Unlock()
+
+	snapshot := make([]EndpointStats, 0, len(latency.stats))
+	for _, stats := range latency.stats {
+		snapshot = append(snapshot, *stats)
+	}
+
+	sort.Slice(snapshot, func(i, j int) bool {
+		if snapshot[i].Command == snapshot[j].Command {
+			return snapshot[i].Method < snapshot[j].Method
+		}
+		return snapshot[i].Command < snapshot[j].Command
+	})
+
+	return snapshot
+
+}
+
+//Reset discards the statistics recorded so far
+func (latency *LatencyStats) Reset() {
+
+	latency.mutex.Lock()
+	defer latency.mutex.Unlock()
+
+	latency.stats = make(map[string]*EndpointStats)
+
+}
+
+func (latency *LatencyStats) record(method Method, command string, duration time.Duration, failed bool) {
+
+	latency.mutex.Lock()
+	defer latency.mutex.Unlock()
+
+	key := string(method) + " " + command
+
+	stats, ok := latency.stats[key]
+	if !ok {
+		stats = &EndpointStats{Method: method, Command: command, Min: duration, Max: duration}
+		latency.stats[key] = stats
+	}
+
+	stats.Count++
+	stats.Total += duration
+
+	if failed {
+		stats.Errors++
+	}
+
+	if duration < stats.Min {
+		stats.Min = duration
+	}
+
+	if duration > stats.Max {
+		stats.Max = duration
+	}
+
+}
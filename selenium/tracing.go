@@ -0,0 +1,44 @@
+package selenium
+
+import "context"
+
+//Tracer starts a span around every command. It is the hook used to attach tracing tools such as OpenTelemetry.
+type Tracer interface {
+	StartSpan(ctx context.Context, name string) (context.Context, Span)
+}
+
+//Span is a traced command started by a Tracer
+type Span interface {
+	SetAttribute(key string, value interface{})
+	End(err error)
+}
+
+//TracingMiddleware starts a span named "<method> <command>" for every command and ends it with the command error
+func TracingMiddleware(tracer Tracer) Middleware {
+
+	return func(next CommandExecutor) CommandExecutor {
+		return CommandExecutorFunc(func(ctx context.Context, method Method, endpoint string, data interface{}) (*Reply, error) {
+
+			ctx, span := tracer.StartSpan(ctx, string(method)+" "+CommandName(endpoint))
+			span.SetAttribute("webdriver.method", string(method))
+			span.SetAttribute("webdriver.endpoint", endpoint)
+
+			reply, err := next.Execute(ctx, method, endpoint, data)
+
+			spanErr := err
+
+			if reply != nil {
+				span.SetAttribute("webdriver.status", reply.StatusCode)
+				if err == nil && reply.StatusCode != 200 {
+					spanErr = reply.GetError()
+				}
+			}
+
+			span.End(spanErr)
+
+			return reply, err
+
+		})
+	}
+
+}
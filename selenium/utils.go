@@ -17,3 +17,29 @@ func Log(r io.Reader) {
 		}
 	}
 }
+
+//ScriptResultReferences returns every WebElement and ShadowRoot contained in a value returned by ExecuteScript or ExecuteAsyncScript
+func ScriptResultReferences(value interface{}) []WebElementUpdater {
+
+	references := make([]WebElementUpdater, 0)
+
+	switch result := value.(type) {
+
+	case WebElementUpdater:
+		references = append(references, result)
+
+	case map[string]interface{}:
+		for _, item := range result {
+			references = append(references, ScriptResultReferences(item)...)
+		}
+
+	case []interface{}:
+		for _, item := range result {
+			references = append(references, ScriptResultReferences(item)...)
+		}
+
+	}
+
+	return references
+
+}
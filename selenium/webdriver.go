@@ -1,8 +1,14 @@
 package selenium
 
-import "./by"
+import (
+	"context"
+
+	"./by"
+)
 
 type WebDriver interface {
+	WithContext(ctx context.Context) WebDriver
+	Use(middlewares ...Middleware)
 	NewSession() (SessionInfo, error)
 	GetTimeouts() (*Timeouts, error)
 	SetTimeouts(*Timeouts) error
@@ -30,6 +36,9 @@ type WebDriver interface {
 	FindElementFromElement(element WebElement, locator *by.Locator) (WebElement, error)
 	FindElementsFromElement(element WebElement, locator *by.Locator) ([]WebElement, error)
 	GetActiveElement() (WebElement, error)
+	GetElementShadowRoot(element WebElement) (ShadowRoot, error)
+	FindElementFromShadowRoot(root ShadowRoot, locator *by.Locator) (WebElement, error)
+	FindElementsFromShadowRoot(root ShadowRoot, locator *by.Locator) ([]WebElement, error)
 	IsElementSelected(element WebElement) (bool, error)
 	IsElementEnabled(element WebElement) (bool, error)
 	GetElementAttribute(element WebElement, name string) (string, error)
@@ -42,4 +51,21 @@ type WebDriver interface {
 	ElementClear(element WebElement) error
 	ElementSendKeys(element WebElement, keys string) error
 	ExecuteScript(script string, args ...interface{}) (interface{}, error)
+	ExecuteAsyncScript(script string, args ...interface{}) (interface{}, error)
+	GetPageSource() (string, error)
+	PerformActions(actions *Actions) error
+	ReleaseActions() error
+	SwitchToAlert() (Alert, error)
+	DismissAlert() error
+	AcceptAlert() error
+	GetAlertText() (string, error)
+	SendAlertText(text string) error
+	GetCookies() ([]*Cookie, error)
+	GetNamedCookie(name string) (*Cookie, error)
+	AddCookie(cookie *Cookie) error
+	DeleteCookie(name string) error
+	DeleteAllCookies() error
+	TakeScreenshot() (Screenshot, error)
+	TakeElementScreenshot(element WebElement) (Screenshot, error)
+	PrintPage(options *PrintOptions) ([]byte, error)
 }
@@ -1,7 +1,11 @@
 package selenium
 
+import "context"
+
 type WebDriverInfo interface {
 	GetURL() string
+	GetExecutor() CommandExecutor
 	GetDesiredCapabilities() Capabilities
 	GetSession() SessionInfo
+	GetContext() context.Context
 }
@@ -2,4 +2,5 @@ package selenium
 
 type WebDriverUpdater interface {
 	SetSession(id string, caps map[string]interface{})
+	SetDialect(dialect Dialect)
 }
@@ -2,6 +2,12 @@ package selenium
 
 import "./by"
 
+//Identifiers used as the key of web element and shadow root reference objects on the wire
+const (
+	WebElementIdentifier = "element-6066-11e4-a52e-4f735466cecf"
+	ShadowRootIdentifier = "shadow-6066-11e4-a52e-4f735466cecf"
+)
+
 type webElement struct {
 	id     string
 	value  string
@@ -101,6 +107,12 @@ func (e *webElement) GetRect() (*Rect, error) { return e.driver.GetElementRect(e
 /* Get element CSS property value. */
 func (e *webElement) GetCSS(name string) (string, error) { return e.driver.GetElementCSS(e, name) }
 
+/* GetShadowRoot returns the shadow root attached to the element. */
+func (e *webElement) GetShadowRoot() (ShadowRoot, error) { return e.driver.GetElementShadowRoot(e) }
+
+/* TakeScreenshot captures the area of the element's bounding rectangle. */
+func (e *webElement) TakeScreenshot() (Screenshot, error) { return e.driver.TakeElementScreenshot(e) }
+
 //WebElement provides an interface to common actions performed on a Selenium WebElement
 type WebElement interface {
 	Click() error
@@ -118,4 +130,6 @@ type WebElement interface {
 	GetProperty(name string) (string, error)
 	GetRect() (*Rect, error)
 	GetCSS(name string) (string, error)
+	GetShadowRoot() (ShadowRoot, error)
+	TakeScreenshot() (Screenshot, error)
 }
@@ -0,0 +1,31 @@
+package webkit
+
+import (
+	"errors"
+
+	"../../selenium"
+)
+
+func init() {
+	selenium.RegisterBrowser("webkit", selenium.Browser{
+		NewOptions: func() selenium.Options { return new(Options) },
+		Start:      start,
+	})
+}
+
+//start starts WebKitWebDriver, found from the WEBKITWEBDRIVER environment variable or $PATH, on a free port
+func start(options selenium.Options, middlewares ...selenium.Middleware) (selenium.Driver, error) {
+
+	webkitOptions, ok := options.(*Options)
+	if !ok {
+		return nil, errors.New("webkit requires *webkit.Options")
+	}
+
+	driver, err := Driver("", 0, webkitOptions, middlewares...)
+	if err != nil {
+		return nil, err
+	}
+
+	return driver, nil
+
+}
@@ -0,0 +1,58 @@
+package webkit
+
+import (
+	"path/filepath"
+	"strings"
+
+	"../../selenium"
+)
+
+type Capabilities struct {
+	selenium.Capabilities
+	BrowserOptions *Options `json:"webkitgtk:browserOptions,omitempty"`
+}
+
+//MarshalJSON encodes the W3C capabilities and the browser options as a single object
+func (caps *Capabilities) MarshalJSON() ([]byte, error) {
+
+	extensions := make(map[string]interface{})
+	if caps.BrowserOptions != nil {
+		extensions["webkitgtk:browserOptions"] = caps.BrowserOptions
+	}
+
+	return selenium.MarshalCapabilities(caps.Capabilities, extensions)
+
+}
+
+//newCapabilities returns the capabilities of a WebKitGTK session using options
+func newCapabilities(options *Options) *Capabilities {
+
+	caps := &Capabilities{selenium.NewCapabilities(), options}
+	caps.SetBrowserName(browserName(options))
+
+	if options != nil {
+		options.Apply(caps)
+	}
+
+	return caps
+
+}
+
+//browserName returns the browser name set in options, or else the base name of their binary, or MiniBrowser
+func browserName(options *Options) string {
+
+	if options == nil {
+		return "MiniBrowser"
+	}
+
+	if options.browserName != "" {
+		return options.browserName
+	}
+
+	if options.Binary != "" {
+		return strings.TrimSuffix(filepath.Base(options.Binary), filepath.Ext(options.Binary))
+	}
+
+	return "MiniBrowser"
+
+}
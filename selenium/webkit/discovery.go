@@ -0,0 +1,11 @@
+package webkit
+
+import "../../selenium"
+
+//DriverEnv is the environment variable naming the WebKitWebDriver binary
+const DriverEnv = "WEBKITWEBDRIVER"
+
+//FindDriver returns the path of WebKitWebDriver from the explicit path, the WEBKITWEBDRIVER environment variable or $PATH
+func FindDriver(path string) (string, error) {
+	return selenium.FindBinary(path, DriverEnv, "WebKitWebDriver")
+}
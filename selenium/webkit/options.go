@@ -0,0 +1,66 @@
+package webkit
+
+import (
+	"fmt"
+
+	"../../selenium"
+)
+
+//Options is an implementation of the webkitgtk:browserOptions key included in desired capabilities
+type Options struct {
+	Binary       string         `json:"binary,omitempty"`
+	Args         []string       `json:"args,omitempty"`
+	Certificates []*Certificate `json:"certificates,omitempty"`
+
+	selenium.BrowserOptions
+
+	//browserName is the browserName capability of the session, see SetBrowserName
+	browserName string
+}
+
+//Certificate is a TLS certificate trusted by the browser for a host
+type Certificate struct {
+	Host            string `json:"host"`
+	CertificateFile string `json:"certificateFile"`
+}
+
+//SetBinary sets the path of the browser binary, MiniBrowser by default
+func (options *Options) SetBinary(binary string) {
+	options.Binary = binary
+}
+
+//SetBrowserName sets the browserName capability matched by WebKitWebDriver, e.g. "Epiphany".
+//It defaults to the base name of the binary, or MiniBrowser when no binary is set.
+func (options *Options) SetBrowserName(name string) {
+	options.browserName = name
+}
+
+//AddArgs adds command line arguments of the browser
+func (options *Options) AddArgs(args ...string) {
+	options.Args = append(options.Args, args...)
+}
+
+//AddCertificate trusts the PEM certificate at path for host
+func (options *Options) AddCertificate(host string, path string) {
+	options.Certificates = append(options.Certificates, &Certificate{Host: host, CertificateFile: path})
+}
+
+//AddPref sets a WebKitSettings property, passed to MiniBrowser as --name=value
+func (options *Options) AddPref(name string, value interface{}) {
+	options.Args = selenium.RemoveArg(options.Args, "--"+name, 0)
+	options.AddArgs(fmt.Sprintf("--%s=%v", name, value))
+}
+
+//SetHeadless runs the browser without a visible window
+func (options *Options) SetHeadless(headless bool) {
+	options.Args = selenium.RemoveArg(options.Args, "--headless", 0)
+	if headless {
+		options.AddArgs("--headless")
+	}
+}
+
+//SetWindowSize sets the initial size of the browser window
+func (options *Options) SetWindowSize(width int, height int) {
+	options.Args = selenium.RemoveArg(options.Args, "--geometry", 0)
+	options.AddArgs(fmt.Sprintf("--geometry=%dx%d", width, height))
+}
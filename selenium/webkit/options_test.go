@@ -0,0 +1,42 @@
+package webkit
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCapabilitiesJSON(t *testing.T) {
+
+	options := new(Options)
+	options.SetBinary("/usr/bin/MiniBrowser")
+	options.SetHeadless(true)
+	options.SetWindowSize(1280, 720)
+	options.AddCertificate("localhost", "/etc/ssl/localhost.pem")
+
+	data, err := json.Marshal(newCapabilities(options))
+	require.NoErrorf(t, err, "Capabilities should be encoded.")
+	require.JSONEqf(t, `{
+		"browserName": "MiniBrowser",
+		"webkitgtk:browserOptions": {
+			"binary": "/usr/bin/MiniBrowser",
+			"args": ["--headless", "--geometry=1280x720"],
+			"certificates": [{"host": "localhost", "certificateFile": "/etc/ssl/localhost.pem"}]
+		}
+	}`, string(data), "Browser options should be sent under webkitgtk:browserOptions.")
+
+}
+
+func TestBrowserName(t *testing.T) {
+
+	require.Equalf(t, "MiniBrowser", browserName(nil), "MiniBrowser should be started by default.")
+
+	options := new(Options)
+	options.SetBinary("/usr/bin/epiphany")
+	require.Equalf(t, "epiphany", browserName(options), "Browser name should be derived from the binary.")
+
+	options.SetBrowserName("Epiphany")
+	require.Equalf(t, "Epiphany", browserName(options), "Browser name should be overridden by the options.")
+
+}
@@ -0,0 +1,33 @@
+package webkit
+
+import "../../selenium"
+
+type webkitDriver struct {
+	*selenium.LocalDriver
+}
+
+//Driver starts the WebKitWebDriver server on the specified port, or a free port if 0, and returns a WebDriver implementation.
+//When path is empty WebKitWebDriver is looked up from the WEBKITWEBDRIVER environment variable and $PATH.
+//Every command, including the new session, is sent through middlewares.
+func Driver(path string, port int, options *Options, middlewares ...selenium.Middleware) (*webkitDriver, error) {
+
+	path, err := FindDriver(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return DriverWithService(selenium.NewService(path, port), options, middlewares...)
+
+}
+
+//DriverWithService starts service, e.g. configured to capture the WebKitWebDriver logs, and returns a WebDriver implementation
+func DriverWithService(service *selenium.Service, options *Options, middlewares ...selenium.Middleware) (*webkitDriver, error) {
+
+	driver, err := selenium.StartLocalDriver(service, newCapabilities(options), middlewares...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &webkitDriver{driver}, nil
+
+}
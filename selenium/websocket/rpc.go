@@ -0,0 +1,257 @@
+package websocket
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+//Router classifies a received message, returning the id of the command it replies to, or else the event it carries.
+//Messages with neither, e.g. malformed ones, are dropped.
+type Router func(data []byte) (id int64, event interface{})
+
+//RPC multiplexes the commands and events of a JSON-RPC like protocol, such as the Chrome DevTools Protocol or
+//WebDriver BiDi, over a connection. Commands are matched with their reply by id and events are queued for their
+//subscribers, so the connection is never blocked by a slow receiver. The framing of the messages is left to the
+//protocol clients. RPC is safe for concurrent use.
+type RPC struct {
+	conn   *Conn
+	route  Router
+	nextID int64
+
+	mutex         sync.Mutex
+	pending       map[int64]chan []byte
+	subscriptions map[*Subscription]bool
+	err           error
+	done          chan struct{}
+}
+
+//NewRPC starts reading the messages of conn, which are classified by route
+func NewRPC(conn *Conn, route Router) *RPC {
+
+	rpc := &RPC{
+		conn:          conn,
+		route:         route,
+		pending:       make(map[int64]chan []byte),
+		subscriptions: make(map[*Subscription]bool),
+		done:          make(chan struct{}),
+	}
+
+	go rpc.read()
+
+	return rpc
+
+}
+
+//NextID returns the id of a new command
+func (rpc *RPC) NextID() int64 {
+	return atomic.AddInt64(&rpc.nextID, 1)
+}
+
+//Call sends the command data, which carries id, and waits for its reply, or until ctx is done
+func (rpc *RPC) Call(ctx context.Context, id int64, data []byte) ([]byte, error) {
+
+	reply := make(chan []byte, 1)
+
+	rpc.mutex.Lock()
+	if rpc.err != nil {
+		rpc.mutex.Unlock()
+		return nil, rpc.err
+	}
+	rpc.pending[id] = reply
+	rpc.mutex.Unlock()
+
+	defer func() {
+		rpc.mutex.Lock()
+		delete(rpc.pending, id)
+		rpc.mutex.Unlock()
+	}()
+
+	err := rpc.conn.WriteMessage(data)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+
+	case data := <-reply:
+		return data, nil
+
+	case <-ctx.Done():
+		return nil, ctx.Err()
+
+	case <-rpc.done:
+		return nil, rpc.err
+
+	}
+
+}
+
+//Subscribe returns a subscription receiving the events accepted by match, until Unsubscribe is called
+//or the connection is closed
+func (rpc *RPC) Subscribe(match func(event interface{}) bool) *Subscription {
+
+	subscription := newSubscription(match)
+
+	rpc.mutex.Lock()
+	if rpc.err != nil {
+		subscription.stop()
+	} else {
+		rpc.subscriptions[subscription] = true
+	}
+	rpc.mutex.Unlock()
+
+	return subscription
+
+}
+
+//Unsubscribe stops the subscription, closing its events channel
+func (rpc *RPC) Unsubscribe(subscription *Subscription) {
+
+	rpc.mutex.Lock()
+	delete(rpc.subscriptions, subscription)
+	rpc.mutex.Unlock()
+
+	subscription.stop()
+
+}
+
+//Close closes the connection, failing the pending commands and stopping the subscriptions
+func (rpc *RPC) Close() error {
+
+	err := rpc.conn.Close()
+	<-rpc.done
+
+	return err
+
+}
+
+func (rpc *RPC) read() {
+
+	for {
+
+		data, err := rpc.conn.ReadMessage()
+		if err != nil {
+			rpc.shutdown(err)
+			return
+		}
+
+		id, event := rpc.route(data)
+
+		rpc.mutex.Lock()
+
+		if event != nil {
+			for subscription := range rpc.subscriptions {
+				subscription.push(event)
+			}
+		} else if reply, ok := rpc.pending[id]; ok {
+			reply <- data
+		}
+
+		rpc.mutex.Unlock()
+
+	}
+
+}
+
+func (rpc *RPC) shutdown(err error) {
+
+	rpc.mutex.Lock()
+	defer rpc.mutex.Unlock()
+
+	rpc.err = err
+	for subscription := range rpc.subscriptions {
+		subscription.stop()
+	}
+	rpc.subscriptions = nil
+
+	close(rpc.done)
+
+}
+
+//Subscription queues the events it matches until they are received from its Events channel
+type Subscription struct {
+	match  func(event interface{}) bool
+	events chan interface{}
+
+	mutex sync.Mutex
+	queue []interface{}
+	wake  chan struct{}
+	done  chan struct{}
+	once  sync.Once
+}
+
+func newSubscription(match func(event interface{}) bool) *Subscription {
+
+	subscription := &Subscription{
+		match:  match,
+		events: make(chan interface{}),
+		wake:   make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+
+	go subscription.forward()
+
+	return subscription
+
+}
+
+//Events returns the channel receiving the events in order, closed once the subscription is stopped
+func (subscription *Subscription) Events() <-chan interface{} {
+	return subscription.events
+}
+
+//Done returns a channel closed once the subscription is stopped
+func (subscription *Subscription) Done() <-chan struct{} {
+	return subscription.done
+}
+
+func (subscription *Subscription) push(event interface{}) {
+
+	if !subscription.match(event) {
+		return
+	}
+
+	subscription.mutex.Lock()
+	subscription.queue = append(subscription.queue, event)
+	subscription.mutex.Unlock()
+
+	select {
+	case subscription.wake <- struct{}{}:
+	default:
+	}
+
+}
+
+func (subscription *Subscription) stop() {
+	subscription.once.Do(func() { close(subscription.done) })
+}
+
+func (subscription *Subscription) forward() {
+
+	defer close(subscription.events)
+
+	for {
+
+		subscription.mutex.Lock()
+		queue := subscription.queue
+		subscription.queue = nil
+		subscription.mutex.Unlock()
+
+		for _, event := range queue {
+			select {
+			case subscription.events <- event:
+			case <-subscription.done:
+				return
+			}
+		}
+
+		select {
+		case <-subscription.wake:
+		case <-subscription.done:
+			return
+		}
+
+	}
+
+}
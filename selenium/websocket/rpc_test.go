@@ -0,0 +1,94 @@
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type rpcMessage struct {
+	ID    int64  `json:"id,omitempty"`
+	Event string `json:"event,omitempty"`
+}
+
+func routeRPCMessage(data []byte) (int64, interface{}) {
+
+	message := new(rpcMessage)
+	if json.Unmarshal(data, message) != nil {
+		return 0, nil
+	}
+
+	if message.Event != "" {
+		return 0, message.Event
+	}
+
+	return message.ID, nil
+
+}
+
+func TestRPC(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		ws, err := Upgrade(w, r)
+		if err != nil {
+			return
+		}
+		defer ws.Close()
+
+		for {
+			data, err := ws.ReadMessage()
+			if err != nil {
+				return
+			}
+			//each command is preceded by an event, as browsers send events before the reply of the command causing them
+			ws.WriteMessage([]byte(`{"event": "started"}`))
+			ws.WriteMessage([]byte(`not json`))
+			ws.WriteMessage(data)
+		}
+
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := Dial(ctx, "ws"+strings.TrimPrefix(server.URL, "http"))
+	require.NoErrorf(t, err, "Handshake should succeed.")
+
+	rpc := NewRPC(conn, routeRPCMessage)
+
+	subscription := rpc.Subscribe(func(event interface{}) bool { return event == "started" })
+	ignored := rpc.Subscribe(func(event interface{}) bool { return false })
+
+	id := rpc.NextID()
+	reply, err := rpc.Call(ctx, id, []byte(`{"id": 1}`))
+	require.NoErrorf(t, err, "Command should be answered.")
+	require.JSONEqf(t, `{"id": 1}`, string(reply), "Reply should be matched by id.")
+
+	select {
+	case event := <-subscription.Events():
+		require.Equalf(t, "started", event, "Event should be received.")
+	case <-ctx.Done():
+		t.Fatal("Event should be received.")
+	}
+
+	rpc.Unsubscribe(ignored)
+	_, ok := <-ignored.Events()
+	require.Falsef(t, ok, "Events channel should be closed once unsubscribed.")
+
+	require.NoErrorf(t, rpc.Close(), "Connection should be closed.")
+
+	_, ok = <-subscription.Events()
+	require.Falsef(t, ok, "Events channels should be closed with the connection.")
+
+	_, err = rpc.Call(ctx, rpc.NextID(), []byte(`{"id": 2}`))
+	require.Errorf(t, err, "Commands should fail once the connection is closed.")
+
+}
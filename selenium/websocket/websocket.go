@@ -0,0 +1,393 @@
+//Package websocket implements the subset of the RFC 6455 WebSocket protocol spoken by browser debugging endpoints,
+//such as the Chrome DevTools Protocol and WebDriver BiDi, and multiplexes the commands and events of these protocols
+package websocket
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xa
+)
+
+//acceptGUID is appended to the handshake key to compute the accept key
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+//closeTimeout bounds the time Close waits to send the close frame
+const closeTimeout = 5 * time.Second
+
+//ErrClosed is returned when using a connection closed by either end
+var ErrClosed = errors.New("websocket: connection closed")
+
+//Conn is a WebSocket connection exchanging messages. ReadMessage must be called from a single goroutine,
+//WriteMessage and Close are safe for concurrent use.
+type Conn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	//client connections mask the frames they send
+	client bool
+
+	writeMutex sync.Mutex
+	closeOnce  sync.Once
+	closed     chan struct{}
+}
+
+func newConn(conn net.Conn, reader *bufio.Reader, client bool) *Conn {
+	return &Conn{conn: conn, reader: reader, client: client, closed: make(chan struct{})}
+}
+
+//Dial opens a WebSocket connection to a ws:// or wss:// URL, aborting the handshake when ctx is done
+func Dial(ctx context.Context, rawURL string) (*Conn, error) {
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	host := u.Host
+	if u.Port() == "" {
+		switch u.Scheme {
+		case "ws":
+			host = net.JoinHostPort(u.Hostname(), "80")
+		case "wss":
+			host = net.JoinHostPort(u.Hostname(), "443")
+		}
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return nil, err
+	}
+
+	switch u.Scheme {
+	case "ws":
+	case "wss":
+		conn = tls.Client(conn, &tls.Config{ServerName: u.Hostname()})
+	default:
+		conn.Close()
+		return nil, fmt.Errorf("websocket: unsupported scheme %q", u.Scheme)
+	}
+
+	ws, err := handshake(ctx, conn, u)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return ws, nil
+
+}
+
+func handshake(ctx context.Context, conn net.Conn, u *url.URL) (*Conn, error) {
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+		defer conn.SetDeadline(time.Time{})
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	request := &http.Request{
+		Method:     http.MethodGet,
+		URL:        &url.URL{Path: u.Path, RawPath: u.RawPath, RawQuery: u.RawQuery},
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header: http.Header{
+			"Upgrade":               {"websocket"},
+			"Connection":            {"Upgrade"},
+			"Sec-Websocket-Key":     {key},
+			"Sec-Websocket-Version": {"13"},
+		},
+		Host: u.Host,
+	}
+
+	if err := request.Write(conn); err != nil {
+		return nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+
+	response, err := http.ReadResponse(reader, request)
+	if err != nil {
+		return nil, err
+	}
+	response.Body.Close()
+
+	if response.StatusCode != http.StatusSwitchingProtocols {
+		return nil, fmt.Errorf("websocket: handshake failed with status %s", response.Status)
+	}
+
+	if response.Header.Get("Sec-Websocket-Accept") != acceptKey(key) {
+		return nil, errors.New("websocket: handshake failed with an invalid accept key")
+	}
+
+	return newConn(conn, reader, true), nil
+
+}
+
+//Upgrade answers the handshake of a WebSocket client and returns the server side of the connection
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+
+	key := r.Header.Get("Sec-Websocket-Key")
+	if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") || key == "" {
+		http.Error(w, "websocket handshake expected", http.StatusBadRequest)
+		return nil, errors.New("websocket: not a handshake request")
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "websocket not supported", http.StatusInternalServerError)
+		return nil, errors.New("websocket: response does not support hijacking")
+	}
+
+	conn, buffer, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n\r\n"
+
+	if _, err := conn.Write([]byte(response)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return newConn(conn, buffer.Reader, false), nil
+
+}
+
+func acceptKey(key string) string {
+
+	hash := sha1.Sum([]byte(key + acceptGUID))
+
+	return base64.StdEncoding.EncodeToString(hash[:])
+
+}
+
+//ReadMessage returns the payload of the next text or binary message, answering pings meanwhile.
+//It returns ErrClosed once the connection is closed.
+func (ws *Conn) ReadMessage() ([]byte, error) {
+
+	var message []byte
+	started := false
+
+	for {
+
+		fin, opcode, payload, err := readFrame(ws.reader)
+		if err != nil {
+			ws.closeConn()
+			select {
+			case <-ws.closed:
+				return nil, ErrClosed
+			default:
+			}
+			return nil, err
+		}
+
+		switch opcode {
+
+		case opPing:
+			if err := ws.writeFrame(opPong, payload); err != nil {
+				return nil, err
+			}
+
+		case opPong:
+
+		case opClose:
+			//echo the close frame, then drop the connection
+			ws.writeFrame(opClose, payload)
+			ws.closeConn()
+			return nil, ErrClosed
+
+		case opText, opBinary, opContinuation:
+
+			if (opcode == opContinuation) != started {
+				ws.closeConn()
+				return nil, errors.New("websocket: unexpected continuation frame")
+			}
+
+			started = true
+			message = append(message, payload...)
+
+			if fin {
+				return message, nil
+			}
+
+		default:
+			ws.closeConn()
+			return nil, fmt.Errorf("websocket: unknown opcode %d", opcode)
+
+		}
+
+	}
+
+}
+
+//WriteMessage sends data as a text message
+func (ws *Conn) WriteMessage(data []byte) error {
+	return ws.writeFrame(opText, data)
+}
+
+//Close sends a normal closure frame and closes the connection
+func (ws *Conn) Close() error {
+
+	select {
+	case <-ws.closed:
+		return nil
+	default:
+	}
+
+	ws.conn.SetWriteDeadline(time.Now().Add(closeTimeout))
+
+	payload := make([]byte, 2)
+	binary.BigEndian.PutUint16(payload, 1000)
+	ws.writeFrame(opClose, payload)
+
+	return ws.closeConn()
+
+}
+
+func (ws *Conn) closeConn() error {
+
+	var err error
+
+	ws.closeOnce.Do(func() {
+		close(ws.closed)
+		err = ws.conn.Close()
+	})
+
+	return err
+
+}
+
+func (ws *Conn) writeFrame(opcode byte, payload []byte) error {
+
+	ws.writeMutex.Lock()
+	defer ws.writeMutex.Unlock()
+
+	select {
+	case <-ws.closed:
+		return ErrClosed
+	default:
+	}
+
+	header := []byte{0x80 | opcode, 0}
+
+	length := len(payload)
+	switch {
+	case length < 126:
+		header[1] = byte(length)
+	case length <= 0xffff:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(length))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(length))
+	}
+
+	if ws.client {
+
+		mask := make([]byte, 4)
+		if _, err := rand.Read(mask); err != nil {
+			return err
+		}
+
+		header[1] |= 0x80
+		header = append(header, mask...)
+
+		masked := make([]byte, length)
+		for i := range payload {
+			masked[i] = payload[i] ^ mask[i%4]
+		}
+		payload = masked
+
+	}
+
+	_, err := ws.conn.Write(append(header, payload...))
+
+	return err
+
+}
+
+//readFrame reads a frame and returns its payload, unmasked
+func readFrame(reader *bufio.Reader) (bool, byte, []byte, error) {
+
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return false, 0, nil, err
+	}
+
+	fin := header[0]&0x80 != 0
+	opcode := header[0] & 0x0f
+	masked := header[1]&0x80 != 0
+	length := uint64(header[1] & 0x7f)
+
+	switch length {
+	case 126:
+		extended := make([]byte, 2)
+		if _, err := io.ReadFull(reader, extended); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(extended))
+	case 127:
+		extended := make([]byte, 8)
+		if _, err := io.ReadFull(reader, extended); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(extended)
+	}
+
+	if length > 1<<31 {
+		return false, 0, nil, fmt.Errorf("websocket: frame of %d bytes is too large", length)
+	}
+
+	mask := make([]byte, 4)
+	if masked {
+		if _, err := io.ReadFull(reader, mask); err != nil {
+			return false, 0, nil, err
+		}
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return false, 0, nil, err
+	}
+
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+
+	return fin, opcode, payload, nil
+
+}
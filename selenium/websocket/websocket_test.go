@@ -0,0 +1,73 @@
+package websocket
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConn(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+		ws, err := Upgrade(w, r)
+		if err != nil {
+			return
+		}
+		defer ws.Close()
+
+		//a ping and a fragmented message, which the client should answer and reassemble
+		ws.writeFrame(opPing, []byte("ping"))
+		ws.conn.Write([]byte{opText, 3, 'h', 'e', 'l'})
+		ws.conn.Write([]byte{0x80 | opContinuation, 2, 'l', 'o'})
+
+		for {
+			message, err := ws.ReadMessage()
+			if err != nil {
+				return
+			}
+			ws.WriteMessage(message)
+		}
+
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ws, err := Dial(ctx, "ws"+strings.TrimPrefix(server.URL, "http")+"/devtools/browser")
+	require.NoErrorf(t, err, "Handshake should succeed.")
+
+	message, err := ws.ReadMessage()
+	require.NoErrorf(t, err, "Fragmented message should be read.")
+	require.Equalf(t, "hello", string(message), "Fragments should be reassembled.")
+
+	large := strings.Repeat("x", 70000)
+	for _, sent := range []string{"{}", strings.Repeat("y", 300), large} {
+		require.NoErrorf(t, ws.WriteMessage([]byte(sent)), "Message should be sent.")
+		message, err := ws.ReadMessage()
+		require.NoErrorf(t, err, "Echo should be read.")
+		require.Equalf(t, sent, string(message), "Echo should match the %d bytes sent.", len(sent))
+	}
+
+	require.NoErrorf(t, ws.Close(), "Connection should be closed.")
+	_, err = ws.ReadMessage()
+	require.Truef(t, errors.Is(err, ErrClosed), "Reading a closed connection should fail.")
+
+}
+
+func TestDialRejectsPlainHTTP(t *testing.T) {
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	_, err := Dial(context.Background(), "ws"+strings.TrimPrefix(server.URL, "http"))
+	require.Errorf(t, err, "Handshake should fail without a WebSocket server.")
+
+}